@@ -1,74 +1,181 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"flag"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"time"
 
+	"backend/internal/authz"
+	"backend/internal/config"
 	"backend/internal/handler"
+	"backend/internal/logging"
+	"backend/internal/metrics"
+	"backend/internal/migrate"
 	"backend/internal/model"
 	"backend/internal/repository"
+	"backend/internal/server"
 	"backend/internal/service"
+	"backend/internal/tracing"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// version y commit se definen al compilar con -ldflags "-X main.version=... -X main.commit=..."
+var (
+	version = "dev"
+	commit  = ""
+)
+
 func main() {
-	// Configuración de la base de datos
-	dbHost := getEnv("DB_HOST", "localhost")
-	dbPort := getEnv("DB_PORT", "5432")
-	dbUser := getEnv("DB_USER", "postgres")
-	dbPassword := getEnv("DB_PASSWORD", "postgres")
-	dbName := getEnv("DB_NAME", "app_db")
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		dbHost, dbUser, dbPassword, dbName, dbPort)
+	switch command {
+	case "serve":
+		flags := flag.NewFlagSet("serve", flag.ExitOnError)
+		cfg := loadConfig(flags, args)
+		serve(cfg, connectDB(cfg))
+	case "migrate":
+		runMigrate(args)
+	case "seed":
+		runSeed(args)
+	case "config":
+		flags := flag.NewFlagSet("config", flag.ExitOnError)
+		loadConfig(flags, args).Print(os.Stdout)
+	default:
+		fatal("comando desconocido; use serve, migrate up|down|status, seed o config", nil, "command", command)
+	}
+}
+
+// loadConfig lee la configuración desde el archivo, el entorno y los flags, y termina
+// el proceso si no es válida. flags puede traer flags propios del subcomando.
+// También configura el logger por defecto con el nivel y el formato indicados.
+func loadConfig(flags *flag.FlagSet, args []string) *config.Config {
+	cfg, err := config.Load(flags, args, os.LookupEnv)
+	if err != nil {
+		fatal("configuración inválida", err)
+	}
+	logger, err := logging.New(os.Stderr, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		fatal("error al configurar los logs", err)
+	}
+	slog.SetDefault(logger)
+	return cfg
+}
+
+// fatal registra el error y termina el proceso. args son pares clave-valor adicionales.
+func fatal(msg string, err error, args ...any) {
+	if err != nil {
+		args = append(args, "error", err)
+	}
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// connectDB abre la conexión a la base de datos con reintentos y configura el pool.
+// Las consultas se registran con el logger por defecto.
+func connectDB(appCfg *config.Config) *gorm.DB {
+	cfg := appCfg.Database
+	gormConfig := &gorm.Config{
+		// TranslateError convierte las violaciones de unicidad y de claves foráneas en errores de GORM
+		TranslateError: true,
+		Logger:         logging.NewGormLogger(slog.Default(), appCfg.Log.SlowQueryThreshold),
+	}
 
-	// Intentar conectar a la base de datos con reintentos
 	var db *gorm.DB
 	var dbErr error
-	maxRetries := 10
-	for i := 0; i < maxRetries; i++ {
-		db, dbErr = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	for i := 0; i < cfg.ConnectRetries; i++ {
+		db, dbErr = gorm.Open(postgres.Open(cfg.DSN()), gormConfig)
 		if dbErr == nil {
 			// Verificar la conexión
-			sqlDB, err := db.DB()
-			if err == nil {
-				err = sqlDB.Ping()
-				if err == nil {
-					log.Printf("✅ Conexión exitosa a la base de datos en %s:%s", dbHost, dbPort)
+			var sqlDB *sql.DB
+			sqlDB, dbErr = db.DB()
+			if dbErr == nil {
+				dbErr = sqlDB.Ping()
+				if dbErr == nil {
+					sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+					sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+					sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+					sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+					slog.Info("conexión exitosa a la base de datos", "database", cfg.Redacted())
 					break
 				}
 			}
 		}
-		log.Printf("Intento %d/%d: Error al conectar a la base de datos: %v", i+1, maxRetries, dbErr)
-		if i < maxRetries-1 {
-			time.Sleep(3 * time.Second)
+		slog.Warn("error al conectar a la base de datos", "attempt", i+1, "max_attempts", cfg.ConnectRetries, "error", dbErr)
+		if i < cfg.ConnectRetries-1 {
+			time.Sleep(cfg.RetryInterval)
 		}
 	}
 
 	if dbErr != nil {
-		log.Fatalf("❌ No se pudo conectar a la base de datos después de %d intentos: %v", maxRetries, dbErr)
+		fatal("no se pudo conectar a la base de datos", dbErr, "attempts", cfg.ConnectRetries)
 	}
+	return db
+}
 
-	// Auto-migrar modelos
-	if err := db.AutoMigrate(&model.Area{}, &model.Persona{}); err != nil {
-		log.Fatalf("❌ Error al realizar la migración: %v", err)
+// serve verifica que la base esté migrada e inicia el servidor HTTP
+func serve(cfg *config.Config, db *gorm.DB) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal("error al obtener la conexión a la base de datos", err)
 	}
+	migrator, err := migrate.New(sqlDB)
+	if err != nil {
+		fatal("error al cargar las migraciones", err)
+	}
+
+	// El servidor no migra la base: se niega a iniciar hasta que se ejecute "migrate up"
+	if err := migrator.Check(context.Background()); err != nil {
+		fatal("ejecute \"migrate up\" antes de iniciar el servidor", err)
+	}
+
+	slog.Info("esquema de la base de datos al día", "version", migrator.Latest())
 
-	log.Println("✅ Migración de la base de datos completada con éxito")
+	// Trazas: un span por petición, por operación de los servicios y por consulta SQL
+	build := buildInfo()
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName:    handler.ServiceName,
+		ServiceVersion: build.Version,
+		Exporter:       cfg.Tracing.Exporter,
+		OTLPEndpoint:   cfg.Tracing.OTLPEndpoint,
+		SampleRatio:    cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		fatal("error al configurar las trazas", err)
+	}
+	if err := tracing.RegisterGORM(db); err != nil {
+		fatal("error al registrar las trazas de la base de datos", err)
+	}
 
-	// Configuración del enrutador Gin
-	r := gin.Default()
+	// Configuración del enrutador Gin. El log de peticiones va primero para registrar también las
+	// que terminan en un panic; las métricas miden todas las peticiones, incluidas las de CORS.
+	if cfg.Log.Level != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	r := gin.New()
+	appMetrics := metrics.New()
+	r.Use(logging.Middleware(slog.Default()), handler.Recovery(), tracing.Middleware(), appMetrics.Middleware())
 
 	// Middleware de CORS
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, If-Match, If-None-Match, "+logging.RequestIDHeader)
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, "+logging.RequestIDHeader)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -81,61 +188,183 @@ func main() {
 	// Inicializar repositorios
 	areaRepo := repository.NewAreaRepository(db)
 	personaRepo := repository.NewPersonaRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	unitOfWork := repository.NewUnitOfWork(db)
 
 	// Inicializar servicios
-	areaService := service.NewAreaService(areaRepo)
-	personaService := service.NewPersonaService(personaRepo)
+	areaService := service.NewAreaService(areaRepo, unitOfWork)
+	personaService := service.NewPersonaService(personaRepo, areaRepo)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, authConfig(cfg.Auth))
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	userService := service.NewUserService(userRepo, areaRepo)
+	auditService := service.NewAuditService(auditRepo)
+	healthService := service.NewHealthService(sqlDB, migrator, cfg.Server.HealthTimeout, build)
+
+	// Crear el usuario administrador inicial si se configuró
+	if cfg.Admin.Username != "" {
+		if err := authService.EnsureUser(context.Background(), cfg.Admin.Username, cfg.Admin.Password, model.RoleAdmin); err != nil {
+			fatal("error al crear el usuario administrador", err)
+		}
+	}
 
 	// Inicializar handlers
 	areaHandler := handler.NewAreaHandler(areaService)
 	personaHandler := handler.NewPersonaHandler(personaService)
+	authHandler := handler.NewAuthHandler(authService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	userHandler := handler.NewUserHandler(userService)
+	auditHandler := handler.NewAuditHandler(auditService, personaService)
+	healthHandler := handler.NewHealthHandler(healthService)
 
-	// Grupo de rutas de la API
-	api := r.Group("/api/v1")
+	// Métricas del pool de conexiones y del negocio
+	appMetrics.RegisterDB(sqlDB, cfg.Database.Name)
+	appMetrics.RegisterAreaConteo(areaService, cfg.Server.HealthTimeout)
+	requireAuth := handler.RequireAuth(authService, apiKeyService)
+
+	// Sondas para el orquestador: /livez no depende de la base y /readyz sí
+	r.GET("/livez", healthHandler.Live)
+	r.GET("/readyz", healthHandler.Ready)
+	r.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+
+	// Grupo de rutas de la API; cada petición tiene un plazo que cancela sus consultas al vencer.
+	// Las exportaciones tienen su propio plazo, que también reemplaza al de escritura.
+	exportTimeouts := map[string]time.Duration{
+		"/api/v1/personas/export":     cfg.Server.ExportTimeout,
+		"/api/v1/areas/conteo/export": cfg.Server.ExportTimeout,
+	}
+	api := r.Group("/api/v1", handler.Deadline(cfg.Server.RequestTimeout, exportTimeouts))
 	{
 		// Ruta de salud
-		api.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{
-				"status":  "ok",
-				"service": "backend-monolito",
-			})
-		})
-
-		// Rutas de áreas
-		areas := api.Group("/areas")
+		api.GET("/health", healthHandler.Health)
+
+		// Rutas de autenticación
+		auth := api.Group("/auth")
 		{
-			areas.POST("", areaHandler.Create)
-			areas.GET("", areaHandler.GetAll)
-			areas.GET("/:id", areaHandler.GetByID)
-			areas.PUT("/:id", areaHandler.Update)
-			areas.DELETE("/:id", areaHandler.Delete)
-			areas.GET("/conteo", areaHandler.GetAreasConConteo)
+			auth.POST("/login", authHandler.Login)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", authHandler.Logout)
+		}
+
+		// Rutas de áreas; cada ruta declara la acción que la política debe autorizar
+		can := func(action authz.Action) gin.HandlerFunc {
+			return handler.Authorize(authz.DefaultPolicy, action)
+		}
+
+		areas := api.Group("/areas", requireAuth)
+		{
+			areas.POST("", can(authz.AreasWrite), areaHandler.Create)
+			areas.GET("", can(authz.AreasRead), areaHandler.GetAll)
+			areas.GET("/:id", can(authz.AreasRead), areaHandler.GetByID)
+			areas.PUT("/:id", can(authz.AreasWrite), areaHandler.Update)
+			areas.PATCH("/:id", can(authz.AreasWrite), areaHandler.Patch)
+			areas.DELETE("/:id", can(authz.AreasDelete), areaHandler.Delete)
+			areas.POST("/:id/restore", can(authz.AreasDelete), areaHandler.Restore)
+			areas.POST("/:id/move-personas", can(authz.AreasWrite), areaHandler.MovePersonas)
+			areas.GET("/conteo", can(authz.AreasRead), areaHandler.GetAreasConConteo)
+			areas.GET("/conteo/export", can(authz.AreasRead), areaHandler.ExportConteo)
 		}
 
 		// Rutas de personas
-		personas := api.Group("/personas")
+		personas := api.Group("/personas", requireAuth)
+		{
+			personas.POST("", can(authz.PersonasWrite), personaHandler.Create)
+			personas.GET("", can(authz.PersonasRead), personaHandler.GetAll)
+			personas.POST("/import", can(authz.PersonasImport), personaHandler.Import)
+			personas.GET("/export", can(authz.PersonasRead), personaHandler.Export)
+			personas.GET("/:id", can(authz.PersonasRead), personaHandler.GetByID)
+			personas.PUT("/:id", can(authz.PersonasWrite), personaHandler.Update)
+			personas.PATCH("/:id", can(authz.PersonasWrite), personaHandler.Patch)
+			personas.DELETE("/:id", can(authz.PersonasDelete), personaHandler.Delete)
+			personas.POST("/:id/restore", can(authz.PersonasDelete), personaHandler.Restore)
+			personas.GET("/email/:email", can(authz.PersonasRead), personaHandler.GetByEmail)
+			personas.GET("/:id/history", can(authz.PersonasRead), auditHandler.PersonaHistory)
+		}
+
+		// Rutas de auditoría
+		audit := api.Group("/audit", requireAuth)
+		{
+			audit.GET("", can(authz.AuditRead), auditHandler.GetAll)
+		}
+
+		// Rutas de API keys para integraciones
+		apiKeys := api.Group("/api-keys", requireAuth, can(authz.APIKeysManage))
+		{
+			apiKeys.POST("", apiKeyHandler.Create)
+			apiKeys.GET("", apiKeyHandler.GetAll)
+			apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+		}
+
+		// Rutas de usuarios
+		users := api.Group("/users", requireAuth, can(authz.UsersManage))
 		{
-			personas.POST("", personaHandler.Create)
-			personas.GET("", personaHandler.GetAll)
-			personas.GET("/:id", personaHandler.GetByID)
-			personas.PUT("/:id", personaHandler.Update)
-			personas.DELETE("/:id", personaHandler.Delete)
-			personas.GET("/email/:email", personaHandler.GetByEmail)
+			users.POST("", userHandler.Create)
 		}
 	}
 
 	// Iniciar servidor
-	port := getEnv("PORT", "8080")
-	log.Printf("🚀 Servidor iniciado en el puerto %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("❌ Error al iniciar el servidor: %v", err)
+	srv := &http.Server{
+		Handler:           r,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	ln, err := net.Listen("tcp", ":"+strconv.Itoa(cfg.Server.Port))
+	if err != nil {
+		fatal("error al iniciar el servidor", err)
+	}
+
+	slog.Info("servidor iniciado", "port", cfg.Server.Port, "version", build.Version)
+	err = server.Run(context.Background(), srv, ln, cfg.Server.ShutdownTimeout,
+		server.Closer{Name: "Pool de conexiones a la base de datos", Close: sqlDB.Close},
+		server.Closer{Name: "Exportador de trazas", Close: func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return shutdownTracing(ctx)
+		}})
+	if err != nil {
+		fatal("error al detener el servidor", err)
+	}
+	slog.Info("servidor detenido")
+}
+
+// buildInfo retorna la versión del binario; sin commit definido al compilar se usa el
+// que registra go build cuando compila dentro de un repositorio git
+func buildInfo() model.BuildInfo {
+	info := model.BuildInfo{Version: version, Commit: commit, StartedAt: time.Now()}
+	if info.Commit == "" {
+		info.Commit = "desconocido"
+		if build, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range build.Settings {
+				if setting.Key == "vcs.revision" {
+					info.Commit = setting.Value
+				}
+			}
+		}
 	}
+	return info
 }
 
-// getEnv obtiene el valor de una variable de entorno o retorna un valor por defecto
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// authConfig arma la configuración de los tokens. Sin clave configurada se usa una clave
+// aleatoria, por lo que las sesiones no sobreviven a un reinicio del servidor.
+func authConfig(cfg config.AuthConfig) service.AuthConfig {
+	auth := service.AuthConfig{
+		Secret:     []byte(cfg.JWTSecret),
+		Issuer:     cfg.Issuer,
+		AccessTTL:  cfg.AccessTTL,
+		RefreshTTL: cfg.RefreshTTL,
 	}
-	return defaultValue
+
+	if len(auth.Secret) == 0 {
+		slog.Warn("JWT_SECRET no está definido; se usará una clave aleatoria")
+		auth.Secret = make([]byte, 32)
+		if _, err := rand.Read(auth.Secret); err != nil {
+			fatal("error al generar la clave de los tokens", err)
+		}
+	}
+
+	return auth
 }
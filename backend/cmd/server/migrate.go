@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"backend/internal/migrate"
+)
+
+// runMigrate ejecuta el subcomando "migrate up|down|status [flags]"; down acepta
+// después de los flags la cantidad de migraciones a revertir
+func runMigrate(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fatal("use migrate up, migrate down [n] o migrate status", nil)
+	}
+	action := args[0]
+	if action != "up" && action != "down" && action != "status" {
+		fatal("subcomando desconocido; use up, down [n] o status", nil, "action", action)
+	}
+
+	flags := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	cfg := loadConfig(flags, args[1:])
+	args = flags.Args()
+	db := connectDB(cfg)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal("error al obtener la conexión a la base de datos", err)
+	}
+	defer sqlDB.Close()
+
+	migrator, err := migrate.New(sqlDB)
+	if err != nil {
+		fatal("error al cargar las migraciones", err)
+	}
+
+	ctx := context.Background()
+	switch action {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			slog.Info("migración aplicada", "version", migration.Version, "name", migration.Name)
+		}
+		if err != nil {
+			fatal("error al migrar", err)
+		}
+		slog.Info("esquema actualizado", "version", migrator.Latest(), "applied", len(applied))
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			steps, err = strconv.Atoi(args[0])
+			if err != nil || steps < 1 {
+				fatal("la cantidad de migraciones a revertir debe ser un entero mayor que 0", nil, "steps", args[0])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			slog.Info("migración revertida", "version", migration.Version, "name", migration.Name)
+		}
+		if err != nil {
+			fatal("error al revertir", err)
+		}
+		slog.Info("migraciones revertidas", "reverted", len(reverted))
+
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			fatal("error al obtener el estado de las migraciones", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSIÓN\tNOMBRE\tAPLICADA")
+		for _, s := range status {
+			applied := "pendiente"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		w.Flush()
+	}
+}
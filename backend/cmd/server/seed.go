@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+
+	"backend/internal/migrate"
+	"backend/internal/seed"
+)
+
+// runSeed ejecuta el subcomando "seed [-profile demo|test|empty] [-file datos.yaml]".
+// Se puede ejecutar varias veces: los registros existentes se actualizan en lugar de duplicarse.
+func runSeed(args []string) {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	profile := flags.String("profile", seed.DefaultProfile, "perfil de datos incluido en el binario (demo, test o empty)")
+	file := flags.String("file", "", "archivo .yaml o .json con los datos; reemplaza al perfil")
+	cfg := loadConfig(flags, args)
+	db := connectDB(cfg)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal("error al obtener la conexión a la base de datos", err)
+	}
+	defer sqlDB.Close()
+
+	ctx := context.Background()
+	migrator, err := migrate.New(sqlDB)
+	if err != nil {
+		fatal("error al cargar las migraciones", err)
+	}
+	if err := migrator.Check(ctx); err != nil {
+		fatal("ejecute \"migrate up\" antes de cargar los datos", err)
+	}
+
+	source := "perfil " + *profile
+	var fixtures *seed.Fixtures
+	if *file != "" {
+		source = *file
+		fixtures, err = seed.LoadFile(*file)
+	} else {
+		fixtures, err = seed.LoadProfile(*profile)
+	}
+	if err != nil {
+		fatal("error al leer los datos", err)
+	}
+
+	result, err := seed.Apply(ctx, db, fixtures)
+	if err != nil {
+		fatal("error al cargar los datos", err)
+	}
+	slog.Info("datos cargados", "source", source, "areas", result.Areas, "personas", result.Personas)
+}
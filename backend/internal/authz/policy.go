@@ -0,0 +1,129 @@
+// Package authz define qué operaciones puede realizar cada rol. Las políticas son datos
+// y se evalúan sin acceder a la base de datos.
+package authz
+
+import (
+	"backend/internal/model"
+	"slices"
+)
+
+// Action es una operación protegida, con la forma recurso:operación
+type Action string
+
+const (
+	AreasRead      Action = "areas:read"
+	AreasWrite     Action = "areas:write"
+	AreasDelete    Action = "areas:delete"
+	PersonasRead   Action = "personas:read"
+	PersonasWrite  Action = "personas:write"
+	PersonasDelete Action = "personas:delete"
+	PersonasImport Action = "personas:import"
+	AuditRead      Action = "audit:read"
+	APIKeysManage  Action = "api-keys:manage"
+	UsersManage    Action = "users:manage"
+)
+
+// Scopes son las acciones que se pueden otorgar a una API key.
+// La administración de API keys queda reservada a los usuarios.
+var Scopes = []Action{AreasRead, AreasWrite, AreasDelete, PersonasRead, PersonasWrite, PersonasDelete, PersonasImport, AuditRead}
+
+// ValidScope indica si scope es una acción que se puede otorgar a una API key
+func ValidScope(scope string) bool {
+	return slices.Contains(Scopes, Action(scope))
+}
+
+// Scope es el alcance con el que un rol puede realizar una acción
+type Scope int
+
+const (
+	// ScopeNone niega la acción
+	ScopeNone Scope = iota
+	// ScopeOwnArea permite la acción solo sobre las personas del área del usuario
+	ScopeOwnArea
+	// ScopeAll permite la acción sobre cualquier registro
+	ScopeAll
+)
+
+// Policy asocia cada rol con las acciones que puede realizar y su alcance.
+// Una acción que no figura para un rol está negada.
+type Policy map[model.Role]map[Action]Scope
+
+// DefaultPolicy es la política de la API
+var DefaultPolicy = Policy{
+	model.RoleAdmin: {
+		AreasRead:      ScopeAll,
+		AreasWrite:     ScopeAll,
+		AreasDelete:    ScopeAll,
+		PersonasRead:   ScopeAll,
+		PersonasWrite:  ScopeAll,
+		PersonasDelete: ScopeAll,
+		PersonasImport: ScopeAll,
+		AuditRead:      ScopeAll,
+		APIKeysManage:  ScopeAll,
+		UsersManage:    ScopeAll,
+	},
+	model.RoleHREditor: {
+		AreasRead:      ScopeAll,
+		AreasWrite:     ScopeAll,
+		PersonasRead:   ScopeAll,
+		PersonasWrite:  ScopeAll,
+		PersonasDelete: ScopeAll,
+		PersonasImport: ScopeAll,
+		AuditRead:      ScopeAll,
+	},
+	model.RoleAreaManager: {
+		AreasRead:     ScopeAll,
+		PersonasRead:  ScopeOwnArea,
+		PersonasWrite: ScopeOwnArea,
+	},
+	model.RoleViewer: {
+		AreasRead:    ScopeAll,
+		PersonasRead: ScopeAll,
+	},
+}
+
+// Decision es el resultado de evaluar una acción para un usuario
+type Decision struct {
+	Allowed bool
+	// AreaID, si no es cero, limita la acción a las personas de esa área
+	AreaID uint
+}
+
+// Unrestricted es la decisión que permite la acción sobre cualquier registro
+var Unrestricted = Decision{Allowed: true}
+
+// Decide evalúa si el usuario puede realizar la acción y con qué alcance.
+// Un alcance de área sin un área asignada al usuario niega la acción.
+// Una API key no tiene rol: puede realizar exactamente las acciones de sus scopes.
+func (p Policy) Decide(principal *model.Principal, action Action) Decision {
+	if principal == nil {
+		return Decision{}
+	}
+
+	if principal.APIKeyID != 0 {
+		if ValidScope(string(action)) && slices.Contains(principal.Scopes, string(action)) {
+			return Unrestricted
+		}
+		return Decision{}
+	}
+
+	switch p[principal.Role][action] {
+	case ScopeAll:
+		return Unrestricted
+	case ScopeOwnArea:
+		if principal.AreaID != 0 {
+			return Decision{Allowed: true, AreaID: principal.AreaID}
+		}
+	}
+	return Decision{}
+}
+
+// Permits indica si la decisión alcanza a una persona del área indicada
+func (d Decision) Permits(areaID uint) bool {
+	return d.Allowed && (d.AreaID == 0 || d.AreaID == areaID)
+}
+
+// Restricted indica si la decisión está limitada a un área
+func (d Decision) Restricted() bool {
+	return d.AreaID != 0
+}
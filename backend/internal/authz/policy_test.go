@@ -0,0 +1,82 @@
+package authz
+
+import (
+	"backend/internal/model"
+	"testing"
+)
+
+// TestDefaultPolicy prueba las reglas principales de la política de la API
+func TestDefaultPolicy(t *testing.T) {
+	// Arrange
+	admin := &model.Principal{UserID: 1, Role: model.RoleAdmin}
+	editor := &model.Principal{UserID: 2, Role: model.RoleHREditor}
+	manager := &model.Principal{UserID: 3, Role: model.RoleAreaManager, AreaID: 2}
+	viewer := &model.Principal{UserID: 4, Role: model.RoleViewer}
+	apiKey := &model.Principal{APIKeyID: 1, Scopes: model.ScopeList{string(PersonasRead)}}
+
+	tests := []struct {
+		name      string
+		principal *model.Principal
+		action    Action
+		expected  Decision
+	}{
+		{"admin elimina áreas", admin, AreasDelete, Unrestricted},
+		{"hr-editor no elimina áreas", editor, AreasDelete, Decision{}},
+		{"hr-editor edita personas", editor, PersonasWrite, Unrestricted},
+		{"area-manager edita personas de su área", manager, PersonasWrite, Decision{Allowed: true, AreaID: 2}},
+		{"area-manager lee personas de su área", manager, PersonasRead, Decision{Allowed: true, AreaID: 2}},
+		{"area-manager no importa personas", manager, PersonasImport, Decision{}},
+		{"viewer lee personas", viewer, PersonasRead, Unrestricted},
+		{"viewer no edita personas", viewer, PersonasWrite, Decision{}},
+		{"sin usuario", nil, AreasRead, Decision{}},
+		{"rol desconocido", &model.Principal{Role: "root"}, AreasRead, Decision{}},
+		{"API key con el scope", apiKey, PersonasRead, Unrestricted},
+		{"API key sin el scope", apiKey, PersonasWrite, Decision{}},
+		{"API key no administra API keys", &model.Principal{APIKeyID: 2, Scopes: model.ScopeList{string(APIKeysManage)}}, APIKeysManage, Decision{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			decision := DefaultPolicy.Decide(tt.principal, tt.action)
+
+			// Assert
+			if decision != tt.expected {
+				t.Errorf("Se esperaba %+v, pero se obtuvo: %+v", tt.expected, decision)
+			}
+		})
+	}
+}
+
+// TestAreaManagerWithoutArea prueba que un area-manager sin área asignada no tenga acceso a personas
+func TestAreaManagerWithoutArea(t *testing.T) {
+	// Arrange
+	manager := &model.Principal{UserID: 3, Role: model.RoleAreaManager}
+
+	// Act
+	decision := DefaultPolicy.Decide(manager, PersonasRead)
+
+	// Assert
+	if decision.Allowed {
+		t.Errorf("Se esperaba la acción negada, pero se obtuvo: %+v", decision)
+	}
+}
+
+// TestDecisionPermits prueba el alcance de una decisión sobre el área de una persona
+func TestDecisionPermits(t *testing.T) {
+	// Arrange
+	ownArea := Decision{Allowed: true, AreaID: 2}
+
+	// Act & Assert
+	if !ownArea.Permits(2) || ownArea.Permits(3) {
+		t.Errorf("Se esperaba que solo alcanzara al área 2")
+	}
+
+	if !Unrestricted.Permits(3) {
+		t.Errorf("Se esperaba que una decisión sin restricción alcanzara a cualquier área")
+	}
+
+	if (Decision{}).Permits(2) {
+		t.Errorf("Se esperaba que una decisión negada no alcanzara a ninguna área")
+	}
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// env simula las variables de entorno del proceso
+func env(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+// load carga la configuración sin imprimir errores de flags
+func load(args []string, values map[string]string) (*Config, error) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return Load(flags, args, env(values))
+}
+
+// writeFile crea un archivo de configuración temporal
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoadPrecedence prueba que los flags pisen al entorno, el entorno al archivo y el archivo a los valores por defecto
+func TestLoadPrecedence(t *testing.T) {
+	// Arrange
+	path := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+database:
+  host: archivo
+  user: archivo
+  name: archivo
+  retry_interval: 1s
+`)
+
+	// Act
+	cfg, err := load(
+		[]string{"-config", path, "-db-host", "flag"},
+		map[string]string{"DB_HOST": "entorno", "DB_USER": "entorno", "DB_NAME": ""},
+	)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if cfg.Database.Host != "flag" {
+		t.Errorf("Se esperaba el host del flag, pero se obtuvo: %s", cfg.Database.Host)
+	}
+
+	if cfg.Database.User != "entorno" {
+		t.Errorf("Se esperaba el usuario del entorno, pero se obtuvo: %s", cfg.Database.User)
+	}
+
+	if cfg.Database.Name != "archivo" || cfg.Server.Port != 9000 || cfg.Database.RetryInterval != time.Second {
+		t.Errorf("Se esperaban los valores del archivo, pero se obtuvo: %+v", cfg)
+	}
+
+	if cfg.Database.ConnectRetries != 10 {
+		t.Errorf("Se esperaba el valor por defecto 10, pero se obtuvo: %d", cfg.Database.ConnectRetries)
+	}
+}
+
+// TestLoadTOML prueba que el archivo de configuración también pueda ser TOML, indicado con CONFIG_FILE
+func TestLoadTOML(t *testing.T) {
+	// Arrange
+	path := writeFile(t, "config.toml", `
+[database]
+user = "app"
+max_open_conns = 50
+conn_max_lifetime = "1h"
+`)
+
+	// Act
+	cfg, err := load(nil, map[string]string{"CONFIG_FILE": path})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if cfg.Database.MaxOpenConns != 50 || cfg.Database.ConnMaxLifetime != time.Hour {
+		t.Errorf("Se esperaban los valores del archivo TOML, pero se obtuvo: %+v", cfg.Database)
+	}
+}
+
+// TestLoadRejectsInvalidValues prueba que la configuración se valide al cargarla
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		env      map[string]string
+		expected string
+	}{
+		{"sin usuario", "", map[string]string{}, "database.user es obligatorio"},
+		{"sslmode desconocido", "", map[string]string{"DB_USER": "app", "DB_SSLMODE": "on"}, "database.sslmode"},
+		{"sslrootcert inexistente", "", map[string]string{"DB_USER": "app", "DB_SSLMODE": "verify-full", "DB_SSLROOTCERT": "/no/existe.crt"}, "database.sslrootcert"},
+		{"secreto corto", "", map[string]string{"DB_USER": "app", "JWT_SECRET": "corto"}, "auth.jwt_secret"},
+		{"duración inválida", "", map[string]string{"DB_USER": "app", "JWT_ACCESS_TTL": "15"}, "JWT_ACCESS_TTL"},
+		{"admin sin contraseña", "", map[string]string{"DB_USER": "app", "ADMIN_USERNAME": "admin"}, "admin.password"},
+		{"URL inválida", "", map[string]string{"DATABASE_URL": "mysql://db"}, "database.url"},
+		{"nivel de log desconocido", "", map[string]string{"DB_USER": "app", "LOG_LEVEL": "verbose"}, "log.level"},
+		{"formato de log desconocido", "", map[string]string{"DB_USER": "app", "LOG_FORMAT": "xml"}, "log.format"},
+		{"clave desconocida", "database:\n  hots: db\n", map[string]string{"DB_USER": "app"}, "database.hots"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var args []string
+			if tt.file != "" {
+				args = []string{"-config", writeFile(t, "config.yaml", tt.file)}
+			}
+
+			// Act
+			_, err := load(args, tt.env)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Se esperaba un error con '%s', pero se obtuvo: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestDSN prueba la URL de conexión armada con los campos o con DATABASE_URL
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{
+			"campos",
+			map[string]string{"DB_HOST": "db", "DB_USER": "app", "DB_PASSWORD": "p@ss word", "DB_SSLMODE": "require"},
+			"postgres://app:p%40ss%20word@db:5432/app_db?connect_timeout=5&sslmode=require",
+		},
+		{
+			"URL con sslmode propio",
+			map[string]string{"DATABASE_URL": "postgres://app:secreto@db/app?sslmode=disable", "DB_HOST": "ignorado"},
+			"postgres://app:secreto@db/app?connect_timeout=5&sslmode=disable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg, err := load(nil, tt.env)
+			if err != nil {
+				t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+			}
+
+			// Act
+			dsn := cfg.Database.DSN()
+
+			// Assert
+			if dsn != tt.expected {
+				t.Errorf("Se esperaba '%s', pero se obtuvo: %s", tt.expected, dsn)
+			}
+		})
+	}
+}
+
+// TestPrintRedactsSecrets prueba que la configuración impresa no muestre contraseñas ni claves
+func TestPrintRedactsSecrets(t *testing.T) {
+	// Arrange
+	cfg, err := load(nil, map[string]string{
+		"DATABASE_URL":   "postgres://app:url-secreta@db/app",
+		"DB_PASSWORD":    "db-secreta",
+		"JWT_SECRET":     "jwt-secreta-0123456789-0123456789",
+		"ADMIN_USERNAME": "admin",
+		"ADMIN_PASSWORD": "admin-secreta",
+	})
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+	var out bytes.Buffer
+
+	// Act
+	err = cfg.Print(&out)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	printed := out.String()
+	for _, secret := range []string{"url-secreta", "db-secreta", "jwt-secreta", "admin-secreta"} {
+		if strings.Contains(printed, secret) {
+			t.Errorf("Se esperaba que '%s' estuviera oculto, pero se obtuvo: %s", secret, printed)
+		}
+	}
+
+	if !strings.Contains(printed, "database.url = postgres://app:xxxxx@db/app") {
+		t.Errorf("Se esperaba la URL con la contraseña oculta, pero se obtuvo: %s", printed)
+	}
+
+	if !strings.Contains(printed, "admin.username = admin") {
+		t.Errorf("Se esperaba el usuario administrador visible, pero se obtuvo: %s", printed)
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyHeader es el encabezado con el que las integraciones envían su API key
+const apiKeyHeader = "X-API-Key"
+
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+func NewAPIKeyHandler(service service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+// Create genera una API key. La clave en claro solo se entrega en esta respuesta.
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req model.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, codeInvalidBody, "Datos inválidos", err)
+		return
+	}
+
+	var createdBy uint
+	if principal := currentPrincipal(c); principal != nil {
+		createdBy = principal.UserID
+	}
+
+	key, plain, err := h.service.Create(c.Request.Context(), req, createdBy)
+	if err != nil {
+		respondError(c, err, "Error al crear la API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API key creada exitosamente; guárdela ahora, no se volverá a mostrar",
+		"data":    key,
+		"key":     plain,
+	})
+}
+
+// GetAll lista las API keys sin exponer las claves
+func (h *APIKeyHandler) GetAll(c *gin.Context) {
+	keys, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Error al obtener las API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": keys,
+	})
+}
+
+// Revoke revoca una API key; desde ese momento se rechazan las peticiones que la usen
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.service.Revoke(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Error al revocar la API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API key revocada exitosamente",
+		"data":    key,
+	})
+}
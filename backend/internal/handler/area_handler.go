@@ -3,8 +3,11 @@ package handler
 import (
 	"backend/internal/model"
 	"backend/internal/service"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,59 +23,81 @@ func NewAreaHandler(service service.AreaService) *AreaHandler {
 // Create crea una nueva área
 func (h *AreaHandler) Create(c *gin.Context) {
 	var area model.Area
-	
+
 	if err := c.ShouldBindJSON(&area); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Datos inválidos",
-			"details": err.Error(),
-		})
+		respondBadRequest(c, codeInvalidBody, "Datos inválidos", err)
 		return
 	}
 
-	if err := h.service.Create(&area); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Error al crear el área",
-			"details": err.Error(),
-		})
+	if err := h.service.Create(c.Request.Context(), &area); err != nil {
+		respondError(c, err, "Error al crear el área")
 		return
 	}
 
+	setETag(c, area.Version)
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Área creada exitosamente",
 		"data":    area,
 	})
 }
 
-// GetAll obtiene todas las áreas
+// GetAll obtiene las áreas de forma paginada, con búsqueda y ordenamiento
 func (h *AreaHandler) GetAll(c *gin.Context) {
-	areas, err := h.service.GetAll()
+	query, err := parseAreaQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Error al obtener las áreas",
-		})
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": areas,
-	})
+	areas, total, err := h.service.GetAll(c.Request.Context(), query)
+	if err != nil {
+		respondError(c, err, "Error al obtener las áreas")
+		return
+	}
+
+	c.JSON(http.StatusOK, paginatedResponse(c, areas, query.ListOptions, total))
+}
+
+// parseAreaQuery lee la búsqueda q, el modo include=conteo y la paginación
+func parseAreaQuery(c *gin.Context) (model.AreaQuery, error) {
+	query := model.AreaQuery{Q: strings.TrimSpace(c.Query("q"))}
+
+	switch include := c.Query("include"); include {
+	case "":
+	case "conteo":
+		query.IncludeConteo = true
+	default:
+		return model.AreaQuery{}, fmt.Errorf("include '%s' no soportado", include)
+	}
+
+	sortFields := model.AreaSortFields
+	if query.IncludeConteo {
+		sortFields = model.AreaConteoSortFields
+	}
+
+	opts, err := parseListOptions(c, sortFields)
+	if err != nil {
+		return model.AreaQuery{}, err
+	}
+	query.ListOptions = opts
+
+	return query, nil
 }
 
 // GetByID obtiene un área por ID
 func (h *AreaHandler) GetByID(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID inválido",
-		})
+	id, ok := parseID(c)
+	if !ok {
 		return
 	}
 
-	area, err := h.service.GetByID(uint(id))
+	area, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Área no encontrada",
-		})
+		respondError(c, err, "Error al obtener el área")
+		return
+	}
+
+	if notModified(c, area.Version) {
 		return
 	}
 
@@ -83,67 +108,201 @@ func (h *AreaHandler) GetByID(c *gin.Context) {
 
 // Update actualiza un área
 func (h *AreaHandler) Update(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID inválido",
-		})
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
 	var area model.Area
 	if err := c.ShouldBindJSON(&area); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Datos inválidos",
-			"details": err.Error(),
-		})
+		respondBadRequest(c, codeInvalidBody, "Datos inválidos", err)
 		return
 	}
 
-	if err := h.service.Update(uint(id), &area); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Error al actualizar el área",
-			"details": err.Error(),
-		})
+	if version != 0 {
+		area.Version = version
+	}
+
+	if err := h.service.Update(c.Request.Context(), id, &area); err != nil {
+		respondError(c, err, "Error al actualizar el área")
 		return
 	}
 
+	setETag(c, area.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Área actualizada exitosamente",
 		"data":    area,
 	})
 }
 
-// Delete elimina un área
+// areaPatchFields son los campos de un área que se pueden modificar con PATCH
+var areaPatchFields = map[string]bool{
+	"nombre":      true,
+	"descripcion": true,
+}
+
+// Patch actualiza parcialmente un área con un documento JSON Merge Patch (RFC 7396)
+func (h *AreaHandler) Patch(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	patch, ok := bindMergePatch(c)
+	if !ok {
+		return
+	}
+
+	area, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Error al actualizar el área")
+		return
+	}
+
+	var patched model.Area
+	if err := applyMergePatch(area, patch, areaPatchFields, &patched); err != nil {
+		respondError(c, err, "Error al actualizar el área")
+		return
+	}
+
+	if version != 0 {
+		patched.Version = version
+	}
+
+	if err := h.service.Update(c.Request.Context(), id, &patched); err != nil {
+		respondError(c, err, "Error al actualizar el área")
+		return
+	}
+
+	setETag(c, patched.Version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Área actualizada exitosamente",
+		"data":    patched,
+	})
+}
+
+// Delete elimina un área. Si tiene personas asignadas se debe indicar
+// ?reassign_to=<id> para moverlas o ?cascade=true para eliminarlas también.
+// Con ?hard=true la eliminación es definitiva.
 func (h *AreaHandler) Delete(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	opts, err := parseAreaDeleteOptions(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID inválido",
-		})
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", err)
 		return
 	}
 
-	if err := h.service.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Error al eliminar el área",
-			"details": err.Error(),
-		})
+	affected, err := h.service.Delete(c.Request.Context(), id, version, opts)
+	if err != nil {
+		respondError(c, err, "Error al eliminar el área")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Área eliminada exitosamente",
+		"personas_afectadas": affected,
+	})
+}
+
+// parseAreaDeleteOptions lee los parámetros reassign_to y cascade
+func parseAreaDeleteOptions(c *gin.Context) (model.AreaDeleteOptions, error) {
+	var opts model.AreaDeleteOptions
+
+	if raw := c.Query("reassign_to"); raw != "" {
+		target, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || target == 0 {
+			return opts, errors.New("reassign_to inválido")
+		}
+		opts.ReassignTo = uint(target)
+	}
+
+	if raw := c.Query("cascade"); raw != "" {
+		cascade, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, errors.New("cascade debe ser true o false")
+		}
+		opts.Cascade = cascade
+	}
+
+	hard, err := parseHard(c)
+	if err != nil {
+		return opts, err
+	}
+	opts.Hard = hard
+
+	return opts, nil
+}
+
+// Restore revierte la eliminación de un área
+func (h *AreaHandler) Restore(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	area, err := h.service.Restore(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Error al restaurar el área")
+		return
+	}
+
+	setETag(c, area.Version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Área restaurada exitosamente",
+		"data":    area,
+	})
+}
+
+// MovePersonas mueve todas las personas del área al área indicada en ?to=<id>.
+// Si alguna persona no se puede mover no se mueve ninguna.
+func (h *AreaHandler) MovePersonas(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	target, err := strconv.ParseUint(c.Query("to"), 10, 32)
+	if err != nil || target == 0 {
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", errors.New("to debe ser el ID del área de destino"))
+		return
+	}
+
+	moved, err := h.service.MovePersonas(c.Request.Context(), id, uint(target))
+	if err != nil {
+		respondError(c, err, "Error al mover las personas")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Área eliminada exitosamente",
+		"message":          "Personas movidas exitosamente",
+		"personas_movidas": moved,
 	})
 }
 
 // GetAreasConConteo obtiene las áreas con el conteo de personas
 func (h *AreaHandler) GetAreasConConteo(c *gin.Context) {
-	areasConConteo, err := h.service.GetAreasConConteo()
+	areasConConteo, err := h.service.GetAreasConConteo(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Error al obtener las áreas con conteo",
-		})
+		respondError(c, err, "Error al obtener las áreas con conteo")
 		return
 	}
 
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditActions son los valores aceptados por el filtro action del listado de auditoría
+var auditActions = map[model.AuditAction]bool{
+	model.AuditCreate:  true,
+	model.AuditUpdate:  true,
+	model.AuditDelete:  true,
+	model.AuditPurge:   true,
+	model.AuditRestore: true,
+}
+
+type AuditHandler struct {
+	service  service.AuditService
+	personas service.PersonaService
+}
+
+func NewAuditHandler(service service.AuditService, personas service.PersonaService) *AuditHandler {
+	return &AuditHandler{service: service, personas: personas}
+}
+
+// GetAll lista las entradas de auditoría de la más reciente a la más antigua,
+// filtradas por entity, id, action y actor
+func (h *AuditHandler) GetAll(c *gin.Context) {
+	query, err := parseAuditQuery(c)
+	if err != nil {
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", err)
+		return
+	}
+
+	entries, total, err := h.service.GetAll(c.Request.Context(), query)
+	if err != nil {
+		respondError(c, err, "Error al obtener la auditoría")
+		return
+	}
+
+	c.JSON(http.StatusOK, paginatedResponse(c, entries, query.ListOptions, total))
+}
+
+// PersonaHistory lista los cambios de una persona, incluidos los anteriores a su eliminación.
+// Un usuario limitado a un área solo ve el historial de las personas vigentes de su área.
+func (h *AuditHandler) PersonaHistory(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	opts, err := parseListOptions(c, nil)
+	if err != nil {
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", err)
+		return
+	}
+
+	decision, ok := accessDecision(c, "Error al obtener el historial de la persona")
+	if !ok {
+		return
+	}
+	if decision.Restricted() {
+		persona, err := h.personas.GetByID(c.Request.Context(), id)
+		if err == nil && !decision.Permits(persona.AreaID) {
+			err = errPersonaOutOfScope()
+		}
+		if err != nil {
+			respondError(c, err, "Error al obtener el historial de la persona")
+			return
+		}
+	}
+
+	query := model.AuditQuery{ListOptions: opts, Entity: model.AuditEntityPersona, EntityID: id}
+	entries, total, err := h.service.GetAll(c.Request.Context(), query)
+	if err != nil {
+		respondError(c, err, "Error al obtener el historial de la persona")
+		return
+	}
+
+	c.JSON(http.StatusOK, paginatedResponse(c, entries, query.ListOptions, total))
+}
+
+// parseAuditQuery lee los filtros entity, id, action y actor y la paginación
+func parseAuditQuery(c *gin.Context) (model.AuditQuery, error) {
+	var query model.AuditQuery
+
+	switch entity := model.AuditEntity(c.Query("entity")); entity {
+	case "", model.AuditEntityArea, model.AuditEntityPersona:
+		query.Entity = entity
+	default:
+		return query, fmt.Errorf("entity debe ser area o persona")
+	}
+
+	if raw := c.Query("id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || id == 0 {
+			return query, fmt.Errorf("id debe ser un entero mayor que 0")
+		}
+		query.EntityID = uint(id)
+	}
+
+	if action := model.AuditAction(c.Query("action")); action != "" {
+		if !auditActions[action] {
+			return query, fmt.Errorf("action debe ser create, update, delete, purge o restore")
+		}
+		query.Action = action
+	}
+	query.Actor = strings.TrimSpace(c.Query("actor"))
+
+	opts, err := parseListOptions(c, nil)
+	if err != nil {
+		return query, err
+	}
+	query.ListOptions = opts
+	return query, nil
+}
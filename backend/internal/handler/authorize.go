@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"backend/internal/authz"
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// decisionKey es la clave del contexto de Gin donde se guarda la decisión de autorización de la ruta
+const decisionKey = "authz_decision"
+
+// Authorize permite la ruta solo si la política autoriza la acción al usuario autenticado.
+// Debe registrarse después de RequireAuth; deja en el contexto el alcance de la decisión.
+func Authorize(policy authz.Policy, action authz.Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := currentPrincipal(c)
+		if principal == nil {
+			respondError(c, service.NewUnauthorizedError("missing_token", "se requiere el encabezado Authorization: Bearer <token>"), "No autenticado")
+			return
+		}
+
+		decision := policy.Decide(principal, action)
+		if !decision.Allowed {
+			err := service.NewForbiddenError("forbidden", "el rol del usuario no permite la acción '"+string(action)+"'")
+			if principal.APIKeyID != 0 {
+				err = service.NewForbiddenError("insufficient_scope", "la API key no tiene el scope '"+string(action)+"'")
+			}
+			respondError(c, err, "Operación no permitida")
+			return
+		}
+
+		c.Set(decisionKey, decision)
+		c.Next()
+	}
+}
+
+// currentPrincipal obtiene el usuario autenticado de la petición, o nil si no lo hay
+func currentPrincipal(c *gin.Context) *model.Principal {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return nil
+	}
+	principal, _ := value.(*model.Principal)
+	return principal
+}
+
+// errMissingDecision indica que una ruta que limita el alcance por área se registró sin Authorize
+var errMissingDecision = errors.New("la ruta no tiene una decisión de autorización")
+
+// accessDecision obtiene la decisión de autorización de la ruta. Si la ruta no se registró con
+// Authorize no se sabe qué alcance tiene el usuario, así que se rechaza la petición con 500 en
+// lugar de permitirla sin restricciones, y se retorna false.
+func accessDecision(c *gin.Context, message string) (authz.Decision, bool) {
+	value, _ := c.Get(decisionKey)
+	decision, ok := value.(authz.Decision)
+	if !ok {
+		slog.ErrorContext(c.Request.Context(), "ruta registrada sin Authorize", "route", c.FullPath())
+		respondError(c, errMissingDecision, message)
+		return authz.Decision{}, false
+	}
+	return decision, true
+}
+
+// requireArea verifica que la decisión alcance al área indicada; si no, responde 403 y retorna false
+func requireArea(c *gin.Context, decision authz.Decision, areaID uint, message string) bool {
+	if decision.Permits(areaID) {
+		return true
+	}
+	respondError(c, errAreaNotAllowed(), message)
+	return false
+}
+
+// requireUnrestricted rechaza con 403 las operaciones que no admiten un alcance limitado a un área
+func requireUnrestricted(c *gin.Context, decision authz.Decision, message string) bool {
+	if !decision.Restricted() {
+		return true
+	}
+	respondError(c, service.NewForbiddenError("forbidden", "la operación requiere acceso a todas las áreas"), message)
+	return false
+}
+
+// errAreaNotAllowed es el error para una operación sobre un área que el usuario no gestiona
+func errAreaNotAllowed() *service.Error {
+	return service.NewForbiddenError("area_not_allowed", "el usuario solo puede operar sobre personas de su área")
+}
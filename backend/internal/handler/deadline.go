@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeDeadlineMargin es el tiempo que se deja después del plazo de una ruta para escribir la
+// respuesta de error antes de que venza el plazo de escritura del servidor
+const writeDeadlineMargin = 5 * time.Second
+
+// Deadline limita cada petición a timeout. El plazo viaja en el contexto de c.Request, por lo
+// que al vencer se cancelan las consultas en curso y el servicio retorna un error que
+// respondError convierte en 504. Si el handler termina sin responder después del plazo, se
+// responde 504 aquí. Con timeout 0 no se impone plazo.
+//
+// routes asigna un plazo propio a algunas rutas, indicadas por su ruta completa, como las
+// exportaciones que se escriben de a poco y pueden tardar más que el resto. En esas rutas también
+// se reemplaza el plazo de escritura del servidor (server.write_timeout), que si no cortaría la
+// respuesta aunque la petición siga dentro de su plazo.
+func Deadline(timeout time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := timeout
+		if routeTimeout, ok := routes[c.FullPath()]; ok {
+			timeout = routeTimeout
+			extendWriteDeadline(c, timeout)
+		}
+
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if err := ctx.Err(); err != nil && !c.Writer.Written() {
+			respondError(c, err, "La petición no se completó a tiempo")
+		}
+	}
+}
+
+// extendWriteDeadline reemplaza el plazo de escritura de la conexión por timeout más un margen;
+// con timeout 0 la conexión queda sin plazo de escritura
+func extendWriteDeadline(c *gin.Context, timeout time.Duration) {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout + writeDeadlineMargin)
+	}
+	// Fuera de un servidor http, como en los tests, el ResponseWriter no admite plazos
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(deadline)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"backend/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Códigos de error propios de la capa HTTP
+const (
+	codeInvalidID            = "invalid_id"
+	codeInvalidBody          = "invalid_body"
+	codeInvalidQuery         = "invalid_query"
+	codeInternal             = "internal_error"
+	codeUnsupportedMediaType = "unsupported_media_type"
+	codePreconditionFailed   = "precondition_failed"
+	codeRequestTimeout       = "request_timeout"
+	codeRequestCanceled      = "request_canceled"
+	codeRequestTooLarge      = "request_too_large"
+)
+
+// errorStatus obtiene el código HTTP y el código de error estable correspondientes a un error del servicio
+func errorStatus(err error) (int, string) {
+	// El plazo de la petición venció (Deadline) o la petición se canceló mientras se consultaba la base
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, codeRequestTimeout
+	case errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable, codeRequestCanceled
+	}
+
+	var domainErr *service.Error
+	if !errors.As(err, &domainErr) {
+		return http.StatusInternalServerError, codeInternal
+	}
+
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound, domainErr.Code
+	case errors.Is(err, service.ErrConflict):
+		return http.StatusConflict, domainErr.Code
+	case errors.Is(err, service.ErrValidation), errors.Is(err, service.ErrForeignKey):
+		return http.StatusUnprocessableEntity, domainErr.Code
+	case errors.Is(err, service.ErrPrecondition):
+		return http.StatusPreconditionFailed, domainErr.Code
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusUnauthorized, domainErr.Code
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden, domainErr.Code
+	}
+	return http.StatusInternalServerError, codeInternal
+}
+
+// respondError escribe la respuesta de error de una operación del servicio.
+// Los errores internos no exponen su detalle al cliente.
+func respondError(c *gin.Context, err error, message string) {
+	status, code := errorStatus(err)
+
+	body := gin.H{
+		"error": message,
+		"code":  code,
+	}
+
+	var domainErr *service.Error
+	if status != http.StatusInternalServerError && errors.As(err, &domainErr) {
+		body["details"] = domainErr.Message
+		if domainErr.Field != "" {
+			body["field"] = domainErr.Field
+		}
+	}
+
+	if status == http.StatusUnauthorized {
+		c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error=%q`, code))
+	}
+
+	c.AbortWithStatusJSON(status, body)
+}
+
+// respondBadRequest escribe una respuesta 400 para una petición mal formada
+func respondBadRequest(c *gin.Context, code, message string, err error) {
+	body := gin.H{
+		"error": message,
+		"code":  code,
+	}
+	if err != nil {
+		body["details"] = err.Error()
+	}
+
+	c.AbortWithStatusJSON(http.StatusBadRequest, body)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etag construye el ETag de un recurso a partir de su versión
+func etag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// setETag agrega el encabezado ETag con la versión del recurso
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", etag(version))
+}
+
+// notModified fija el ETag y, si coincide con If-None-Match, responde 304 y retorna true
+func notModified(c *gin.Context, version uint) bool {
+	current := etag(version)
+	setETag(c, version)
+
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			c.AbortWithStatus(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion lee If-Match y retorna la versión esperada, o 0 si no se indicó o es "*".
+// Solo se acepta un ETag fuerte; cualquier otro valor no puede coincidir y se responde 412.
+func ifMatchVersion(c *gin.Context) (uint, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	if len(header) > 2 && strings.HasPrefix(header, `"`) && strings.HasSuffix(header, `"`) {
+		version, err := strconv.ParseUint(header[1:len(header)-1], 10, 32)
+		if err == nil && version > 0 {
+			return uint(version), true
+		}
+	}
+
+	c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{
+		"error": "If-Match no coincide con la versión actual",
+		"code":  codePreconditionFailed,
+	})
+	return 0, false
+}
@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"backend/internal/model"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// exportFlushEvery es la cantidad de filas tras la cual se envía al cliente lo ya escrito
+const exportFlushEvery = 500
+
+// exportContentTypes asocia cada formato de exportación con su tipo de contenido
+var exportContentTypes = map[model.ExportFormat]string{
+	model.ExportCSV:   "text/csv; charset=utf-8",
+	model.ExportXLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	model.ExportJSONL: "application/x-ndjson",
+}
+
+// Columnas de cada exportación, en el orden en que se escriben
+var (
+	personaExportColumns    = []string{"id", "nombre", "email", "area_id", "area", "created_at", "updated_at"}
+	areaConteoExportColumns = []string{"id", "nombre", "descripcion", "personas"}
+)
+
+// exportWriter escribe las filas de una exportación en un formato de archivo
+type exportWriter interface {
+	WriteRow(values []interface{}) error
+	Flush() error
+	Close() error
+}
+
+// Export descarga las personas que cumplen los mismos filtros y orden que el listado, sin paginar.
+// Las filas se leen de la base con un cursor y se escriben a medida que llegan.
+func (h *PersonaHandler) Export(c *gin.Context) {
+	format, err := parseExportFormat(c)
+	if err != nil {
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", err)
+		return
+	}
+
+	query, err := parsePersonaQuery(c)
+	if err != nil {
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", err)
+		return
+	}
+
+	if !scopePersonaQuery(c, &query, "Error al exportar las personas") {
+		return
+	}
+
+	export, err := startExport(c, format, "personas", personaExportColumns)
+	if err != nil {
+		respondError(c, err, "Error al exportar las personas")
+		return
+	}
+
+	err = h.service.Export(c.Request.Context(), query, func(row *model.PersonaExport) error {
+		return export.write(row.ID, row.Nombre, row.Email, row.AreaID, row.AreaNombre, row.CreatedAt, row.UpdatedAt)
+	})
+	export.finish(err, "Error al exportar las personas")
+}
+
+// ExportConteo descarga las áreas con el conteo de personas
+func (h *AreaHandler) ExportConteo(c *gin.Context) {
+	format, err := parseExportFormat(c)
+	if err != nil {
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", err)
+		return
+	}
+
+	areasConConteo, err := h.service.GetAreasConConteo(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Error al exportar las áreas con conteo")
+		return
+	}
+
+	export, err := startExport(c, format, "areas-conteo", areaConteoExportColumns)
+	if err != nil {
+		respondError(c, err, "Error al exportar las áreas con conteo")
+		return
+	}
+
+	for _, area := range areasConConteo {
+		if err = export.write(area.ID, area.Nombre, area.Descripcion, area.Personas); err != nil {
+			break
+		}
+	}
+	export.finish(err, "Error al exportar las áreas con conteo")
+}
+
+// parseExportFormat lee el parámetro format; por defecto la exportación es CSV
+func parseExportFormat(c *gin.Context) (model.ExportFormat, error) {
+	format := model.ExportFormat(c.DefaultQuery("format", string(model.ExportCSV)))
+	if _, ok := exportContentTypes[format]; !ok {
+		return "", fmt.Errorf("format debe ser csv, xlsx o jsonl")
+	}
+	return format, nil
+}
+
+// newExportWriter crea el escritor del formato indicado y escribe el encabezado cuando el formato lo tiene
+func newExportWriter(format model.ExportFormat, w io.Writer, columns []string) (exportWriter, error) {
+	switch format {
+	case model.ExportXLSX:
+		return newXLSXExportWriter(w, columns)
+	case model.ExportJSONL:
+		return &jsonlExportWriter{w: bufio.NewWriter(w), columns: columns}, nil
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{w: cw}, nil
+}
+
+// exportResponse escribe una exportación en la respuesta y envía al cliente
+// lo acumulado cada exportFlushEvery filas
+type exportResponse struct {
+	c      *gin.Context
+	writer exportWriter
+	count  int
+}
+
+// startExport prepara los encabezados de la respuesta y el escritor de la exportación.
+// Los datos se envían a medida que se escriben, por lo que el status 200 queda fijo
+// desde que la primera parte llega al cliente.
+func startExport(c *gin.Context, format model.ExportFormat, name string, columns []string) (*exportResponse, error) {
+	writer, err := newExportWriter(format, c.Writer, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), format)
+	c.Header("Content-Type", exportContentTypes[format])
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	return &exportResponse{c: c, writer: writer}, nil
+}
+
+// write agrega una fila a la exportación
+func (e *exportResponse) write(values ...interface{}) error {
+	if err := e.writer.WriteRow(values); err != nil {
+		return err
+	}
+	e.count++
+	if e.count%exportFlushEvery != 0 {
+		return nil
+	}
+	if err := e.writer.Flush(); err != nil {
+		return err
+	}
+	e.c.Writer.Flush()
+	return nil
+}
+
+// finish cierra la exportación. Si falló antes de enviar datos responde el error. Si ya se
+// enviaron filas el status 200 no se puede cambiar: registra el error y corta la conexión sin
+// terminar la respuesta, para que el cliente vea una descarga fallida y no un archivo que
+// parece completo.
+func (e *exportResponse) finish(err error, message string) {
+	if err == nil {
+		err = e.writer.Close()
+	}
+	if err == nil {
+		return
+	}
+	if xlsx, ok := e.writer.(*xlsxExportWriter); ok {
+		xlsx.file.Close()
+	}
+
+	if !e.c.Writer.Written() {
+		e.c.Writer.Header().Del("Content-Disposition")
+		respondError(e.c, err, message)
+		return
+	}
+	slog.ErrorContext(e.c.Request.Context(), "exportación interrumpida después de enviar datos",
+		"route", e.c.FullPath(), "rows", e.count, "error", err)
+	panic(http.ErrAbortHandler)
+}
+
+// csvExportWriter escribe las filas como CSV con encabezado
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (e *csvExportWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatExportValue(value)
+		if _, ok := value.(string); ok {
+			record[i] = escapeFormula(record[i])
+		}
+	}
+	return e.w.Write(record)
+}
+
+// escapeFormula antepone un apóstrofo a los textos que una planilla de cálculo interpretaría
+// como fórmula, para que un nombre o email cargado por un usuario no se ejecute al abrir el CSV
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (e *csvExportWriter) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportWriter) Close() error {
+	return e.Flush()
+}
+
+// formatExportValue convierte un valor en texto para un archivo CSV
+func formatExportValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return fmt.Sprint(value)
+}
+
+// jsonlExportWriter escribe cada fila como un objeto JSON por línea, con las claves en el orden de las columnas
+type jsonlExportWriter struct {
+	w       *bufio.Writer
+	columns []string
+}
+
+func (e *jsonlExportWriter) WriteRow(values []interface{}) error {
+	e.w.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(e.columns[i])
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		e.w.Write(key)
+		e.w.WriteByte(':')
+		e.w.Write(raw)
+	}
+	e.w.WriteByte('}')
+	return e.w.WriteByte('\n')
+}
+
+func (e *jsonlExportWriter) Flush() error {
+	return e.w.Flush()
+}
+
+func (e *jsonlExportWriter) Close() error {
+	return e.Flush()
+}
+
+// xlsxExportWriter escribe las filas en una hoja de cálculo. El StreamWriter de excelize
+// guarda las filas en un archivo temporal, de modo que no se acumulan en memoria;
+// el archivo se envía completo al cerrar porque un XLSX es un ZIP.
+type xlsxExportWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXExportWriter(w io.Writer, columns []string) (*xlsxExportWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		return nil, err
+	}
+
+	e := &xlsxExportWriter{w: w, file: file, stream: stream}
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	if err := e.WriteRow(header); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *xlsxExportWriter) WriteRow(values []interface{}) error {
+	e.row++
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	return e.stream.SetRow(cell, values)
+}
+
+func (e *xlsxExportWriter) Flush() error {
+	return nil
+}
+
+func (e *xlsxExportWriter) Close() error {
+	defer e.file.Close()
+	if err := e.stream.Flush(); err != nil {
+		return err
+	}
+	return e.file.Write(e.w)
+}
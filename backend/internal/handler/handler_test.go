@@ -1,24 +1,36 @@
 package handler
 
 import (
+	"backend/internal/authz"
 	"backend/internal/model"
+	"backend/internal/service"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
 )
 
 // Mock del servicio de áreas
 type mockAreaService struct {
-	areas      []model.Area
-	shouldFail bool
+	areas             []model.Area
+	shouldFail        bool
+	lastQuery         model.AreaQuery
+	lastDeleteOptions model.AreaDeleteOptions
+	lastUpdate        *model.Area
 }
 
-func (m *mockAreaService) Create(area *model.Area) error {
+func (m *mockAreaService) Create(ctx context.Context, area *model.Area) error {
 	if m.shouldFail {
 		return errors.New("service error")
 	}
@@ -27,14 +39,15 @@ func (m *mockAreaService) Create(area *model.Area) error {
 	return nil
 }
 
-func (m *mockAreaService) GetAll() ([]model.Area, error) {
+func (m *mockAreaService) GetAll(ctx context.Context, query model.AreaQuery) ([]model.Area, int64, error) {
+	m.lastQuery = query
 	if m.shouldFail {
-		return nil, errors.New("service error")
+		return nil, 0, errors.New("service error")
 	}
-	return m.areas, nil
+	return m.areas, int64(len(m.areas)), nil
 }
 
-func (m *mockAreaService) GetByID(id uint) (*model.Area, error) {
+func (m *mockAreaService) GetByID(ctx context.Context, id uint) (*model.Area, error) {
 	if m.shouldFail {
 		return nil, errors.New("service error")
 	}
@@ -44,24 +57,33 @@ func (m *mockAreaService) GetByID(id uint) (*model.Area, error) {
 			return &area, nil
 		}
 	}
-	return nil, errors.New("area not found")
+	return nil, service.NewNotFoundError("area_not_found", "área no encontrada")
 }
 
-func (m *mockAreaService) Update(id uint, area *model.Area) error {
+func (m *mockAreaService) Update(ctx context.Context, id uint, area *model.Area) error {
 	if m.shouldFail {
 		return errors.New("service error")
 	}
+	m.lastUpdate = area
 	return nil
 }
 
-func (m *mockAreaService) Delete(id uint) error {
+func (m *mockAreaService) Delete(ctx context.Context, id, version uint, opts model.AreaDeleteOptions) (int64, error) {
+	m.lastDeleteOptions = opts
 	if m.shouldFail {
-		return errors.New("service error")
+		return 0, errors.New("service error")
 	}
-	return nil
+	return 0, nil
+}
+
+func (m *mockAreaService) Restore(ctx context.Context, id uint) (*model.Area, error) {
+	if m.shouldFail {
+		return nil, errors.New("service error")
+	}
+	return m.GetByID(ctx, id)
 }
 
-func (m *mockAreaService) GetAreasConConteo() ([]model.AreaConConteo, error) {
+func (m *mockAreaService) GetAreasConConteo(ctx context.Context) ([]model.AreaConConteo, error) {
 	if m.shouldFail {
 		return nil, errors.New("service error")
 	}
@@ -72,13 +94,31 @@ func (m *mockAreaService) GetAreasConConteo() ([]model.AreaConConteo, error) {
 	}, nil
 }
 
+func (m *mockAreaService) MovePersonas(ctx context.Context, fromID, toID uint) (int64, error) {
+	if m.shouldFail {
+		return 0, errors.New("service error")
+	}
+	if fromID == toID {
+		return 0, service.NewValidationError("invalid_move_target", "to", "no se puede mover las personas a la misma área")
+	}
+	return 2, nil
+}
+
 // Mock del servicio de personas
 type mockPersonaService struct {
 	personas   []model.Persona
 	shouldFail bool
+	lastQuery  model.PersonaQuery
+	purged     []uint
+	imported   []model.ImportRow
+	importOpts model.ImportOptions
+	// blockUntilDone hace que GetAll espere a que venza o se cancele el contexto
+	blockUntilDone bool
+	// exportErr es el error que retorna Export después de enviar todas las filas
+	exportErr error
 }
 
-func (m *mockPersonaService) Create(persona *model.Persona) error {
+func (m *mockPersonaService) Create(ctx context.Context, persona *model.Persona) error {
 	if m.shouldFail {
 		return errors.New("service error")
 	}
@@ -86,7 +126,7 @@ func (m *mockPersonaService) Create(persona *model.Persona) error {
 	// Verificar email duplicado
 	for _, p := range m.personas {
 		if p.Email == persona.Email {
-			return errors.New("email already exists")
+			return service.NewConflictError("email_taken", "el correo electrónico ya está registrado")
 		}
 	}
 	
@@ -95,14 +135,34 @@ func (m *mockPersonaService) Create(persona *model.Persona) error {
 	return nil
 }
 
-func (m *mockPersonaService) GetAll() ([]model.Persona, error) {
+func (m *mockPersonaService) GetAll(ctx context.Context, query model.PersonaQuery) ([]model.Persona, int64, error) {
+	m.lastQuery = query
+	if m.blockUntilDone {
+		<-ctx.Done()
+		return nil, 0, ctx.Err()
+	}
 	if m.shouldFail {
-		return nil, errors.New("service error")
+		return nil, 0, errors.New("service error")
 	}
-	return m.personas, nil
+	return m.personas, int64(len(m.personas)), nil
 }
 
-func (m *mockPersonaService) GetByID(id uint) (*model.Persona, error) {
+func (m *mockPersonaService) Export(ctx context.Context, query model.PersonaQuery, fn func(row *model.PersonaExport) error) error {
+	m.lastQuery = query
+	if m.shouldFail {
+		return errors.New("service error")
+	}
+
+	for _, persona := range m.personas {
+		row := model.PersonaExport{ID: persona.ID, Nombre: persona.Nombre, Email: persona.Email, AreaID: persona.AreaID}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return m.exportErr
+}
+
+func (m *mockPersonaService) GetByID(ctx context.Context, id uint) (*model.Persona, error) {
 	if m.shouldFail {
 		return nil, errors.New("service error")
 	}
@@ -112,10 +172,10 @@ func (m *mockPersonaService) GetByID(id uint) (*model.Persona, error) {
 			return &persona, nil
 		}
 	}
-	return nil, errors.New("persona not found")
+	return nil, service.NewNotFoundError("persona_not_found", "persona no encontrada")
 }
 
-func (m *mockPersonaService) GetByEmail(email string) (*model.Persona, error) {
+func (m *mockPersonaService) GetByEmail(ctx context.Context, email string) (*model.Persona, error) {
 	if m.shouldFail {
 		return nil, errors.New("service error")
 	}
@@ -125,23 +185,143 @@ func (m *mockPersonaService) GetByEmail(email string) (*model.Persona, error) {
 			return &persona, nil
 		}
 	}
-	return nil, errors.New("persona not found")
+	return nil, service.NewNotFoundError("persona_not_found", "persona no encontrada")
+}
+
+func (m *mockPersonaService) Update(ctx context.Context, id uint, persona *model.Persona) error {
+	if m.shouldFail {
+		return errors.New("service error")
+	}
+	return nil
 }
 
-func (m *mockPersonaService) Update(id uint, persona *model.Persona) error {
+func (m *mockPersonaService) Delete(ctx context.Context, id, version uint) error {
 	if m.shouldFail {
 		return errors.New("service error")
 	}
 	return nil
 }
 
-func (m *mockPersonaService) Delete(id uint) error {
+func (m *mockPersonaService) Purge(ctx context.Context, id, version uint) error {
 	if m.shouldFail {
 		return errors.New("service error")
 	}
+	m.purged = append(m.purged, id)
+	return nil
+}
+
+func (m *mockPersonaService) Restore(ctx context.Context, id uint) (*model.Persona, error) {
+	if m.shouldFail {
+		return nil, errors.New("service error")
+	}
+	return m.GetByID(ctx, id)
+}
+
+func (m *mockPersonaService) Import(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions) (*model.ImportReport, error) {
+	if m.shouldFail {
+		return nil, errors.New("service error")
+	}
+	m.imported = rows
+	m.importOpts = opts
+
+	report := &model.ImportReport{Mode: opts.Mode, DryRun: opts.DryRun, Total: len(rows), Valid: len(rows)}
+	if !opts.DryRun {
+		report.Created = len(rows)
+	}
+	return report, nil
+}
+
+// Mock del servicio de autenticación: acepta el usuario "admin" con contraseña "secreto123"
+// y los tokens de acceso de mockPrincipals
+type mockAuthService struct{}
+
+// mockPrincipals asocia cada token de acceso de prueba con su usuario
+var mockPrincipals = map[string]*model.Principal{
+	"token-valido":  {UserID: 1, Username: "admin", Role: model.RoleAdmin},
+	"token-viewer":  {UserID: 2, Username: "lector", Role: model.RoleViewer},
+	"token-manager": {UserID: 3, Username: "jefa", Role: model.RoleAreaManager, AreaID: 2},
+}
+
+func (m *mockAuthService) Login(ctx context.Context, username, password string) (*model.TokenPair, error) {
+	if username != "admin" || password != "secreto123" {
+		return nil, service.NewUnauthorizedError("invalid_credentials", "usuario o contraseña incorrectos")
+	}
+	return &model.TokenPair{AccessToken: "token-valido", RefreshToken: "renovacion", TokenType: "Bearer", ExpiresIn: 900}, nil
+}
+
+func (m *mockAuthService) Refresh(ctx context.Context, refreshToken string) (*model.TokenPair, error) {
+	return m.Login(ctx, "admin", "secreto123")
+}
+
+func (m *mockAuthService) Logout(ctx context.Context, refreshToken string) error {
+	return nil
+}
+
+func (m *mockAuthService) Authenticate(accessToken string) (*model.Principal, error) {
+	principal, ok := mockPrincipals[accessToken]
+	if !ok {
+		return nil, service.NewUnauthorizedError("invalid_token", "el token de acceso no es válido o expiró")
+	}
+	return principal, nil
+}
+
+func (m *mockAuthService) EnsureUser(ctx context.Context, username, password string, role model.Role) error {
 	return nil
 }
 
+// Mock del servicio de auditoría
+type mockAuditService struct {
+	entries   []model.AuditEntry
+	lastQuery model.AuditQuery
+}
+
+func (m *mockAuditService) GetAll(ctx context.Context, query model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	m.lastQuery = query
+	return m.entries, int64(len(m.entries)), nil
+}
+
+// Mock del servicio de usuarios
+type mockUserService struct {
+	created *model.UserRequest
+}
+
+func (m *mockUserService) Create(ctx context.Context, request model.UserRequest) (*model.User, error) {
+	m.created = &request
+	user := &model.User{Username: request.Username, PasswordHash: "hash", Role: request.Role, AreaID: request.AreaID}
+	user.ID = 4
+	return user, nil
+}
+
+// Mock del servicio de API keys: acepta las claves de mockAPIKeys
+type mockAPIKeyService struct{}
+
+// mockAPIKeys asocia cada API key de prueba con sus scopes
+var mockAPIKeys = map[string]*model.Principal{
+	"ak_lectura":   {Username: "api-key:reportes", APIKeyID: 1, Scopes: model.ScopeList{"personas:read"}},
+	"ak_escritura": {Username: "api-key:sincronizador", APIKeyID: 2, Scopes: model.ScopeList{"personas:read", "areas:write"}},
+}
+
+func (m *mockAPIKeyService) Create(ctx context.Context, request model.APIKeyRequest, createdBy uint) (*model.APIKey, string, error) {
+	key := &model.APIKey{ID: 3, Name: request.Name, Prefix: "ak_nueva", Scopes: request.Scopes, CreatedBy: createdBy}
+	return key, "ak_nueva-clave", nil
+}
+
+func (m *mockAPIKeyService) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	return []model.APIKey{}, nil
+}
+
+func (m *mockAPIKeyService) Revoke(ctx context.Context, id uint) (*model.APIKey, error) {
+	return nil, service.NewNotFoundError("api_key_not_found", "API key no encontrada")
+}
+
+func (m *mockAPIKeyService) Authenticate(ctx context.Context, key string) (*model.Principal, error) {
+	principal, ok := mockAPIKeys[key]
+	if !ok {
+		return nil, service.NewUnauthorizedError("invalid_api_key", "la API key no es válida")
+	}
+	return principal, nil
+}
+
 // TestGetAllAreasHandler prueba el endpoint GET /areas
 func TestGetAllAreasHandler(t *testing.T) {
 	// Arrange
@@ -174,18 +354,74 @@ func TestGetAllAreasHandler(t *testing.T) {
 		t.Errorf("Se esperaba status 200, pero se obtuvo: %d", w.Code)
 	}
 
-	var response map[string][]model.Area
+	var response struct {
+		Data []model.Area `json:"data"`
+	}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	if err != nil {
 		t.Errorf("Error al decodificar respuesta: %v", err)
 	}
 
-	areas := response["data"]
+	areas := response.Data
 	if len(areas) != 2 {
 		t.Errorf("Se esperaban 2 áreas, pero se obtuvieron: %d", len(areas))
 	}
 }
 
+// TestGetAllAreasHandlerIncludeConteo prueba la búsqueda y el modo include=conteo en GET /areas
+func TestGetAllAreasHandlerIncludeConteo(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockService := &mockAreaService{}
+
+	handler := NewAreaHandler(mockService)
+
+	router := gin.Default()
+	router.GET("/areas", handler.GetAll)
+
+	req, _ := http.NewRequest("GET", "/areas?q=ventas&include=conteo&sort=-personas", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("Se esperaba status 200, pero se obtuvo: %d", w.Code)
+	}
+
+	if mockService.lastQuery.Q != "ventas" || !mockService.lastQuery.IncludeConteo {
+		t.Errorf("Query inesperada: %+v", mockService.lastQuery)
+	}
+
+	if len(mockService.lastQuery.Sort) != 1 || !mockService.lastQuery.Sort[0].Desc {
+		t.Errorf("Orden inesperado: %+v", mockService.lastQuery.Sort)
+	}
+}
+
+// TestGetAllAreasHandlerSortWithoutConteo prueba que no se pueda ordenar por personas sin include=conteo
+func TestGetAllAreasHandlerSortWithoutConteo(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	handler := NewAreaHandler(&mockAreaService{})
+
+	router := gin.Default()
+	router.GET("/areas", handler.GetAll)
+
+	req, _ := http.NewRequest("GET", "/areas?sort=personas", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Se esperaba status 400, pero se obtuvo: %d", w.Code)
+	}
+}
+
 // TestGetAllAreasHandlerError prueba el manejo de errores en GET /areas
 func TestGetAllAreasHandlerError(t *testing.T) {
 	// Arrange
@@ -253,6 +489,177 @@ func TestGetAreasConConteoHandler(t *testing.T) {
 	}
 }
 
+// TestDeleteAreaHandlerOptions prueba la lectura de reassign_to y cascade en DELETE /areas/:id
+func TestDeleteAreaHandlerOptions(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockService := &mockAreaService{}
+
+	handler := NewAreaHandler(mockService)
+
+	router := gin.Default()
+	router.DELETE("/areas/:id", handler.Delete)
+
+	req, _ := http.NewRequest("DELETE", "/areas/1?reassign_to=2", nil)
+	w := httptest.NewRecorder()
+
+	badReq, _ := http.NewRequest("DELETE", "/areas/1?cascade=quizas", nil)
+	badW := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+	router.ServeHTTP(badW, badReq)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("Se esperaba status 200, pero se obtuvo: %d", w.Code)
+	}
+
+	if mockService.lastDeleteOptions.ReassignTo != 2 {
+		t.Errorf("Se esperaba reassign_to 2, pero se obtuvo: %d", mockService.lastDeleteOptions.ReassignTo)
+	}
+
+	if badW.Code != http.StatusBadRequest {
+		t.Errorf("Se esperaba status 400, pero se obtuvo: %d", badW.Code)
+	}
+}
+
+// TestPatchAreaHandler prueba que PATCH /areas/:id aplique un JSON Merge Patch
+func TestPatchAreaHandler(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	area := model.Area{Nombre: "Ventas", Descripcion: "Área de ventas"}
+	area.ID = 1
+
+	mockService := &mockAreaService{
+		areas:      []model.Area{area},
+		shouldFail: false,
+	}
+
+	handler := NewAreaHandler(mockService)
+
+	router := gin.Default()
+	router.PATCH("/areas/:id", handler.Patch)
+
+	req, _ := http.NewRequest("PATCH", "/areas/1", bytes.NewBufferString(`{"descripcion": null}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("Se esperaba status 200, pero se obtuvo: %d", w.Code)
+	}
+
+	if mockService.lastUpdate == nil {
+		t.Fatal("Se esperaba que se actualizara el área")
+	}
+
+	if mockService.lastUpdate.Nombre != "Ventas" {
+		t.Errorf("Se esperaba conservar 'Ventas', pero se obtuvo: %s", mockService.lastUpdate.Nombre)
+	}
+
+	if mockService.lastUpdate.Descripcion != "" {
+		t.Errorf("Se esperaba descripción vacía, pero se obtuvo: %s", mockService.lastUpdate.Descripcion)
+	}
+}
+
+// TestPatchAreaHandlerInvalid prueba los rechazos de PATCH /areas/:id
+func TestPatchAreaHandlerInvalid(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	area := model.Area{Nombre: "Ventas", Descripcion: "Área de ventas"}
+	area.ID = 1
+
+	handler := NewAreaHandler(&mockAreaService{areas: []model.Area{area}})
+
+	router := gin.Default()
+	router.PATCH("/areas/:id", handler.Patch)
+
+	cases := []struct {
+		body        string
+		contentType string
+		status      int
+	}{
+		{`{"nombre": null}`, "application/merge-patch+json", http.StatusUnprocessableEntity},
+		{`{"ID": 5}`, "application/merge-patch+json", http.StatusUnprocessableEntity},
+		{`["nombre"]`, "application/merge-patch+json", http.StatusBadRequest},
+		{`{"nombre": "Otra"}`, "text/plain", http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range cases {
+		req, _ := http.NewRequest("PATCH", "/areas/1", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		if w.Code != tc.status {
+			t.Errorf("%s: se esperaba status %d, pero se obtuvo: %d", tc.body, tc.status, w.Code)
+		}
+	}
+}
+
+// TestAreaHandlerETag prueba el ETag, If-None-Match e If-Match en las rutas de áreas
+func TestAreaHandlerETag(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	area := model.Area{Nombre: "Ventas", Descripcion: "Área de ventas", Version: 3}
+	area.ID = 1
+
+	handler := NewAreaHandler(&mockAreaService{areas: []model.Area{area}})
+
+	router := gin.Default()
+	router.GET("/areas/:id", handler.GetByID)
+	router.DELETE("/areas/:id", handler.Delete)
+
+	getReq, _ := http.NewRequest("GET", "/areas/1", nil)
+	getW := httptest.NewRecorder()
+
+	cachedReq, _ := http.NewRequest("GET", "/areas/1", nil)
+	cachedReq.Header.Set("If-None-Match", `"3"`)
+	cachedW := httptest.NewRecorder()
+
+	weakReq, _ := http.NewRequest("DELETE", "/areas/1", nil)
+	weakReq.Header.Set("If-Match", `W/"3"`)
+	weakW := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(getW, getReq)
+	router.ServeHTTP(cachedW, cachedReq)
+	router.ServeHTTP(weakW, weakReq)
+
+	// Assert
+	if etag := getW.Header().Get("ETag"); etag != `"3"` {
+		t.Errorf(`Se esperaba ETag "3", pero se obtuvo: %s`, etag)
+	}
+
+	if cachedW.Code != http.StatusNotModified {
+		t.Errorf("Se esperaba status 304, pero se obtuvo: %d", cachedW.Code)
+	}
+
+	if cachedW.Body.Len() != 0 {
+		t.Errorf("No se esperaba cuerpo en la respuesta 304, pero se obtuvo: %s", cachedW.Body.String())
+	}
+
+	if weakW.Code != http.StatusPreconditionFailed {
+		t.Errorf("Se esperaba status 412, pero se obtuvo: %d", weakW.Code)
+	}
+}
+
+// unrestricted simula la decisión que deja Authorize para un usuario con acceso a todas las áreas
+func unrestricted(c *gin.Context) {
+	c.Set(decisionKey, authz.Unrestricted)
+}
+
 // TestGetAllPersonasHandler prueba el endpoint GET /personas
 func TestGetAllPersonasHandler(t *testing.T) {
 	// Arrange
@@ -280,7 +687,7 @@ func TestGetAllPersonasHandler(t *testing.T) {
 	handler := NewPersonaHandler(mockService)
 	
 	router := gin.Default()
-	router.GET("/personas", handler.GetAll)
+	router.GET("/personas", unrestricted, handler.GetAll)
 	
 	req, _ := http.NewRequest("GET", "/personas", nil)
 	w := httptest.NewRecorder()
@@ -293,69 +700,146 @@ func TestGetAllPersonasHandler(t *testing.T) {
 		t.Errorf("Se esperaba status 200, pero se obtuvo: %d", w.Code)
 	}
 
-	var response map[string][]model.Persona
+	var response struct {
+		Data []model.Persona `json:"data"`
+		Meta struct {
+			Total int64 `json:"total"`
+		} `json:"meta"`
+	}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	if err != nil {
 		t.Errorf("Error al decodificar respuesta: %v", err)
 	}
 
-	personas := response["data"]
+	personas := response.Data
 	if len(personas) != 2 {
 		t.Errorf("Se esperaban 2 personas, pero se obtuvieron: %d", len(personas))
 	}
+
+	if response.Meta.Total != 2 {
+		t.Errorf("Se esperaba un total de 2, pero se obtuvo: %d", response.Meta.Total)
+	}
 }
 
-// TestCreatePersonaHandler prueba el endpoint POST /personas
-func TestCreatePersonaHandler(t *testing.T) {
+// TestGetAllPersonasHandlerPagination prueba los enlaces de paginación en GET /personas
+func TestGetAllPersonasHandlerPagination(t *testing.T) {
 	// Arrange
 	gin.SetMode(gin.TestMode)
-	
+
+	personas := make([]model.Persona, 5)
 	mockService := &mockPersonaService{
-		personas:   []model.Persona{},
+		personas:   personas,
 		shouldFail: false,
 	}
-	
+
 	handler := NewPersonaHandler(mockService)
-	
+
 	router := gin.Default()
-	router.POST("/personas", handler.Create)
-	
-	newPersona := model.Persona{
-		Nombre:   "Carlos Ruiz",
-		Email:    "carlos@test.com",
-		AreaID:   1,
-	}
-	
-	jsonData, _ := json.Marshal(newPersona)
-	req, _ := http.NewRequest("POST", "/personas", bytes.NewBuffer(jsonData))
-	req.Header.Set("Content-Type", "application/json")
+	router.GET("/personas", unrestricted, handler.GetAll)
+
+	req, _ := http.NewRequest("GET", "/personas?page=2&page_size=2&sort=nombre,-created_at", nil)
 	w := httptest.NewRecorder()
 
 	// Act
 	router.ServeHTTP(w, req)
 
 	// Assert
-	if w.Code != http.StatusCreated {
-		t.Errorf("Se esperaba status 201, pero se obtuvo: %d", w.Code)
+	if w.Code != http.StatusOK {
+		t.Errorf("Se esperaba status 200, pero se obtuvo: %d", w.Code)
 	}
 
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	if err != nil {
+	var response struct {
+		Links struct {
+			Next *string `json:"next"`
+			Prev *string `json:"prev"`
+		} `json:"links"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Errorf("Error al decodificar respuesta: %v", err)
 	}
 
-	data := response["data"].(map[string]interface{})
-	nombre := data["nombre"].(string)
-	email := data["email"].(string)
-	id := uint(data["ID"].(float64))
-	
-	if nombre != "Carlos Ruiz" {
-		t.Errorf("Se esperaba 'Carlos Ruiz', pero se obtuvo: %s", nombre)
+	if response.Links.Next == nil || *response.Links.Next != "/personas?page=3&page_size=2&sort=nombre%2C-created_at" {
+		t.Errorf("Enlace next inesperado: %v", response.Links.Next)
 	}
-	
-	if email != "carlos@test.com" {
-		t.Errorf("Se esperaba 'carlos@test.com', pero se obtuvo: %s", email)
+
+	if response.Links.Prev == nil || *response.Links.Prev != "/personas?page=1&page_size=2&sort=nombre%2C-created_at" {
+		t.Errorf("Enlace prev inesperado: %v", response.Links.Prev)
+	}
+}
+
+// TestGetAllPersonasHandlerInvalidSort prueba que se rechace un campo de orden desconocido
+func TestGetAllPersonasHandlerInvalidSort(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	handler := NewPersonaHandler(&mockPersonaService{})
+
+	router := gin.Default()
+	router.GET("/personas", unrestricted, handler.GetAll)
+
+	req, _ := http.NewRequest("GET", "/personas?sort=password", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Se esperaba status 400, pero se obtuvo: %d", w.Code)
+	}
+}
+
+// TestCreatePersonaHandler prueba el endpoint POST /personas
+func TestCreatePersonaHandler(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	
+	mockService := &mockPersonaService{
+		personas:   []model.Persona{},
+		shouldFail: false,
+	}
+	
+	handler := NewPersonaHandler(mockService)
+	
+	router := gin.Default()
+	router.POST("/personas", unrestricted, handler.Create)
+	
+	newPersona := model.Persona{
+		Nombre:   "Carlos Ruiz",
+		Email:    "carlos@test.com",
+		AreaID:   1,
+	}
+	
+	jsonData, _ := json.Marshal(newPersona)
+	req, _ := http.NewRequest("POST", "/personas", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusCreated {
+		t.Errorf("Se esperaba status 201, pero se obtuvo: %d", w.Code)
+	}
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	if err != nil {
+		t.Errorf("Error al decodificar respuesta: %v", err)
+	}
+
+	data := response["data"].(map[string]interface{})
+	nombre := data["nombre"].(string)
+	email := data["email"].(string)
+	id := uint(data["ID"].(float64))
+	
+	if nombre != "Carlos Ruiz" {
+		t.Errorf("Se esperaba 'Carlos Ruiz', pero se obtuvo: %s", nombre)
+	}
+	
+	if email != "carlos@test.com" {
+		t.Errorf("Se esperaba 'carlos@test.com', pero se obtuvo: %s", email)
 	}
 
 	if id == 0 {
@@ -376,7 +860,7 @@ func TestCreatePersonaHandlerInvalidJSON(t *testing.T) {
 	handler := NewPersonaHandler(mockService)
 	
 	router := gin.Default()
-	router.POST("/personas", handler.Create)
+	router.POST("/personas", unrestricted, handler.Create)
 	
 	invalidJSON := []byte(`{"nombre": "Test", "email": }`)
 	req, _ := http.NewRequest("POST", "/personas", bytes.NewBuffer(invalidJSON))
@@ -404,7 +888,7 @@ func TestCreatePersonaHandlerServiceError(t *testing.T) {
 	handler := NewPersonaHandler(mockService)
 	
 	router := gin.Default()
-	router.POST("/personas", handler.Create)
+	router.POST("/personas", unrestricted, handler.Create)
 	
 	newPersona := model.Persona{
 		Nombre:   "Test User",
@@ -421,7 +905,1219 @@ func TestCreatePersonaHandlerServiceError(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	// Assert
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Se esperaba status 400, pero se obtuvo: %d", w.Code)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Se esperaba status 500, pero se obtuvo: %d", w.Code)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Errorf("Error al decodificar respuesta: %v", err)
+	}
+
+	if response["code"] != "internal_error" {
+		t.Errorf("Se esperaba el código 'internal_error', pero se obtuvo: %v", response["code"])
+	}
+
+	if _, ok := response["details"]; ok {
+		t.Error("No se esperaba el detalle de un error interno en la respuesta")
+	}
+}
+
+// TestCreatePersonaHandlerDuplicateEmail prueba que un email duplicado responda 409 con su código
+func TestCreatePersonaHandlerDuplicateEmail(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	existingPersona := model.Persona{
+		Nombre: "Ana García",
+		Email:  "ana@test.com",
+		AreaID: 1,
+	}
+	existingPersona.ID = 1
+
+	mockService := &mockPersonaService{
+		personas:   []model.Persona{existingPersona},
+		shouldFail: false,
+	}
+
+	handler := NewPersonaHandler(mockService)
+
+	router := gin.Default()
+	router.POST("/personas", unrestricted, handler.Create)
+
+	jsonData, _ := json.Marshal(model.Persona{Nombre: "Otra Ana", Email: "ana@test.com", AreaID: 1})
+	req, _ := http.NewRequest("POST", "/personas", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusConflict {
+		t.Errorf("Se esperaba status 409, pero se obtuvo: %d", w.Code)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Errorf("Error al decodificar respuesta: %v", err)
+	}
+
+	if response["code"] != "email_taken" {
+		t.Errorf("Se esperaba el código 'email_taken', pero se obtuvo: %v", response["code"])
+	}
+}
+
+// TestGetPersonaByIDHandlerNotFound prueba que una persona inexistente responda 404
+func TestGetPersonaByIDHandlerNotFound(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	handler := NewPersonaHandler(&mockPersonaService{})
+
+	router := gin.Default()
+	router.GET("/personas/:id", unrestricted, handler.GetByID)
+
+	req, _ := http.NewRequest("GET", "/personas/42", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Se esperaba status 404, pero se obtuvo: %d", w.Code)
+	}
+}
+
+// TestPersonaHandlerDeletedRecords prueba el listado de eliminadas y la eliminación definitiva
+func TestPersonaHandlerDeletedRecords(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	persona := model.Persona{Nombre: "Juan Pérez", Email: "juan@test.com", AreaID: 1}
+	persona.ID = 1
+
+	mockService := &mockPersonaService{
+		personas:   []model.Persona{persona},
+		shouldFail: false,
+	}
+
+	handler := NewPersonaHandler(mockService)
+
+	router := gin.Default()
+	router.GET("/personas", unrestricted, handler.GetAll)
+	router.DELETE("/personas/:id", unrestricted, handler.Delete)
+	router.POST("/personas/:id/restore", unrestricted, handler.Restore)
+
+	listReq, _ := http.NewRequest("GET", "/personas?deleted=only", nil)
+	listW := httptest.NewRecorder()
+
+	purgeReq, _ := http.NewRequest("DELETE", "/personas/1?hard=true", nil)
+	purgeW := httptest.NewRecorder()
+
+	restoreReq, _ := http.NewRequest("POST", "/personas/1/restore", nil)
+	restoreW := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(listW, listReq)
+	router.ServeHTTP(purgeW, purgeReq)
+	router.ServeHTTP(restoreW, restoreReq)
+
+	// Assert
+	if mockService.lastQuery.Deleted != model.DeletedOnly {
+		t.Errorf("Se esperaba deleted=only, pero se obtuvo: %q", mockService.lastQuery.Deleted)
+	}
+
+	if purgeW.Code != http.StatusOK || len(mockService.purged) != 1 {
+		t.Errorf("Se esperaba una eliminación definitiva, pero se obtuvo status %d y %v", purgeW.Code, mockService.purged)
+	}
+
+	if restoreW.Code != http.StatusOK {
+		t.Errorf("Se esperaba status 200, pero se obtuvo: %d", restoreW.Code)
+	}
+}
+
+// TestImportPersonasHandlerCSV prueba la lectura de un CSV en POST /personas/import
+func TestImportPersonasHandlerCSV(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockService := &mockPersonaService{
+		personas:   []model.Persona{},
+		shouldFail: false,
+	}
+
+	handler := NewPersonaHandler(mockService)
+
+	router := gin.Default()
+	router.POST("/personas/import", unrestricted, handler.Import)
+
+	csvData := "Nombre,Email,area_id,area\nAna García,ana@test.com,1,\nLuis Díaz, luis@test.com,,Ventas\nEva Ruiz,eva@test.com,uno,\n"
+	req, _ := http.NewRequest("POST", "/personas/import?mode=best_effort&dry_run=true", bytes.NewBufferString(csvData))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Errorf("Se esperaba status 200, pero se obtuvo: %d", w.Code)
+	}
+
+	if mockService.importOpts.Mode != model.ImportBestEffort || !mockService.importOpts.DryRun {
+		t.Errorf("Se esperaba modo best_effort con dry run, pero se obtuvo: %+v", mockService.importOpts)
+	}
+
+	if len(mockService.imported) != 3 {
+		t.Fatalf("Se esperaban 3 filas, pero se obtuvo: %d", len(mockService.imported))
+	}
+
+	second := mockService.imported[1]
+	if second.Line != 3 || second.Persona.Email != "luis@test.com" || second.AreaNombre != "Ventas" {
+		t.Errorf("Se esperaba la fila 3 con área por nombre, pero se obtuvo: %+v", second)
+	}
+
+	if mockService.imported[2].ParseError == "" {
+		t.Errorf("Se esperaba un error de lectura por area_id inválido")
+	}
+}
+
+// TestImportPersonasHandlerUnsupportedMediaType prueba que se rechacen formatos distintos de CSV y JSON
+func TestImportPersonasHandlerUnsupportedMediaType(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	handler := NewPersonaHandler(&mockPersonaService{})
+
+	router := gin.Default()
+	router.POST("/personas/import", unrestricted, handler.Import)
+
+	req, _ := http.NewRequest("POST", "/personas/import", bytes.NewBufferString("<personas/>"))
+	req.Header.Set("Content-Type", "application/xml")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Se esperaba status 415, pero se obtuvo: %d", w.Code)
+	}
+}
+
+// TestImportPersonasHandlerTooLarge prueba que un cuerpo mayor que el límite se rechace con 413
+func TestImportPersonasHandlerTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "CSV",
+			contentType: "text/csv",
+			body:        "nombre,email,area_id\n" + strings.Repeat("a", int(maxImportBodyBytes)) + ",ana@test.com,1\n",
+		},
+		{
+			name:        "JSON",
+			contentType: "application/json",
+			body:        `[{"nombre": "` + strings.Repeat("a", int(maxImportBodyBytes)) + `"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockPersonaService{}
+			router := gin.New()
+			router.POST("/personas/import", unrestricted, NewPersonaHandler(mockService).Import)
+
+			req, _ := http.NewRequest("POST", "/personas/import", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("Se esperaba status 413, pero se obtuvo: %d", w.Code)
+			}
+			if !strings.Contains(w.Body.String(), `"request_too_large"`) {
+				t.Errorf("Se esperaba el código request_too_large, pero se obtuvo: %s", w.Body.String())
+			}
+			if mockService.imported != nil {
+				t.Errorf("Se esperaba que no se llamara al servicio")
+			}
+		})
+	}
+}
+
+// TestExportPersonasHandler prueba la exportación de personas en CSV y JSON Lines
+func TestExportPersonasHandler(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	persona1 := model.Persona{Nombre: "Ana García", Email: "ana@test.com", AreaID: 1}
+	persona1.ID = 1
+
+	persona2 := model.Persona{Nombre: "Luis, Díaz", Email: "luis@test.com", AreaID: 2}
+	persona2.ID = 2
+
+	mockService := &mockPersonaService{
+		personas:   []model.Persona{persona1, persona2},
+		shouldFail: false,
+	}
+
+	handler := NewPersonaHandler(mockService)
+
+	router := gin.Default()
+	router.GET("/personas/export", unrestricted, handler.Export)
+
+	csvReq, _ := http.NewRequest("GET", "/personas/export?area_id=2&sort=-nombre", nil)
+	csvRecorder := httptest.NewRecorder()
+
+	jsonlReq, _ := http.NewRequest("GET", "/personas/export?format=jsonl", nil)
+	jsonlRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(csvRecorder, csvReq)
+	csvQuery := mockService.lastQuery
+	router.ServeHTTP(jsonlRecorder, jsonlReq)
+
+	// Assert
+	if csvRecorder.Code != http.StatusOK || csvRecorder.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Errorf("Se esperaba un CSV con status 200, pero se obtuvo: %d %s", csvRecorder.Code, csvRecorder.Header().Get("Content-Type"))
+	}
+
+	if csvQuery.AreaID != 2 || len(csvQuery.Sort) != 1 {
+		t.Errorf("Se esperaban los filtros del listado, pero se obtuvo: %+v", csvQuery)
+	}
+
+	lines := strings.Split(strings.TrimSpace(csvRecorder.Body.String()), "\n")
+	if len(lines) != 3 || lines[0] != "id,nombre,email,area_id,area,created_at,updated_at" {
+		t.Errorf("Se esperaba el encabezado y 2 filas, pero se obtuvo: %q", lines)
+	}
+
+	if !strings.HasPrefix(lines[2], `2,"Luis, Díaz",luis@test.com,2,`) {
+		t.Errorf("Se esperaba el nombre con coma entre comillas, pero se obtuvo: %s", lines[2])
+	}
+
+	var first map[string]interface{}
+	jsonlLines := strings.Split(strings.TrimSpace(jsonlRecorder.Body.String()), "\n")
+	if err := json.Unmarshal([]byte(jsonlLines[0]), &first); err != nil || len(jsonlLines) != 2 {
+		t.Fatalf("Se esperaban 2 líneas JSON, pero se obtuvo: %q (%v)", jsonlLines, err)
+	}
+
+	if first["email"] != "ana@test.com" || first["area_id"] != float64(1) {
+		t.Errorf("Se esperaba la primera persona, pero se obtuvo: %v", first)
+	}
+}
+
+// TestExportPersonasHandlerErrors prueba los errores antes de enviar la exportación
+func TestExportPersonasHandlerErrors(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockService := &mockPersonaService{
+		personas:   []model.Persona{},
+		shouldFail: true,
+	}
+
+	handler := NewPersonaHandler(mockService)
+
+	router := gin.Default()
+	router.GET("/personas/export", unrestricted, handler.Export)
+
+	formatReq, _ := http.NewRequest("GET", "/personas/export?format=pdf", nil)
+	formatRecorder := httptest.NewRecorder()
+
+	failReq, _ := http.NewRequest("GET", "/personas/export", nil)
+	failRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(formatRecorder, formatReq)
+	router.ServeHTTP(failRecorder, failReq)
+
+	// Assert
+	if formatRecorder.Code != http.StatusBadRequest {
+		t.Errorf("Se esperaba status 400, pero se obtuvo: %d", formatRecorder.Code)
+	}
+
+	if failRecorder.Code != http.StatusInternalServerError || failRecorder.Header().Get("Content-Disposition") != "" {
+		t.Errorf("Se esperaba status 500 sin adjunto, pero se obtuvo: %d %s", failRecorder.Code, failRecorder.Header().Get("Content-Disposition"))
+	}
+}
+
+// TestExportPersonasHandlerFormulas prueba que los textos que una planilla ejecutaría como fórmula se escapen en el CSV
+func TestExportPersonasHandlerFormulas(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	persona := model.Persona{Nombre: "=HYPERLINK(\"http://x\")", Email: "@sum@test.com", AreaID: 1}
+	persona.ID = 1
+
+	router := gin.New()
+	router.GET("/personas/export", unrestricted, NewPersonaHandler(&mockPersonaService{personas: []model.Persona{persona}}).Export)
+
+	req, _ := http.NewRequest("GET", "/personas/export", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], `1,"'=HYPERLINK(""http://x"")",'@sum@test.com,1,`) {
+		t.Errorf("Se esperaban el nombre y el email con apóstrofo, pero se obtuvo: %q", lines)
+	}
+}
+
+// TestExportPersonasHandlerAbort prueba que una exportación que falla después de enviar filas
+// corte la conexión en lugar de terminar la respuesta como si estuviera completa
+func TestExportPersonasHandlerAbort(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	personas := make([]model.Persona, exportFlushEvery)
+	for i := range personas {
+		personas[i] = model.Persona{Nombre: "Ana", Email: fmt.Sprintf("ana%d@test.com", i), AreaID: 1}
+	}
+	mockService := &mockPersonaService{personas: personas, exportErr: errors.New("conexión perdida")}
+
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/personas/export", unrestricted, NewPersonaHandler(mockService).Export)
+
+	req, _ := http.NewRequest("GET", "/personas/export", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	recovered := func() (recovered interface{}) {
+		defer func() { recovered = recover() }()
+		router.ServeHTTP(w, req)
+		return nil
+	}()
+
+	// Assert
+	if recovered != http.ErrAbortHandler {
+		t.Errorf("Se esperaba un pánico con http.ErrAbortHandler, pero se obtuvo: %v", recovered)
+	}
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "id,nombre,email") {
+		t.Errorf("Se esperaba el inicio de la exportación con status 200, pero se obtuvo: %d", w.Code)
+	}
+}
+
+// TestRecovery prueba que un pánico de un handler se responda con 500 y el código estable
+func TestRecovery(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/panic", func(c *gin.Context) { panic("falla inesperada") })
+
+	req, _ := http.NewRequest("GET", "/panic", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), `"internal_error"`) {
+		t.Errorf("Se esperaba status 500 con internal_error, pero se obtuvo: %d %s", w.Code, w.Body.String())
+	}
+}
+
+// TestExportAreasConteoHandlerXLSX prueba la exportación del conteo de personas por área como XLSX
+func TestExportAreasConteoHandlerXLSX(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	handler := NewAreaHandler(&mockAreaService{})
+
+	router := gin.Default()
+	router.GET("/areas/conteo/export", handler.ExportConteo)
+
+	req, _ := http.NewRequest("GET", "/areas/conteo/export?format=xlsx", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("Se esperaba status 200, pero se obtuvo: %d", w.Code)
+	}
+
+	file, err := excelize.OpenReader(w.Body)
+	if err != nil {
+		t.Fatalf("Se esperaba un XLSX válido, pero se obtuvo: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := file.GetRows("Sheet1")
+	if err != nil || len(rows) != 3 {
+		t.Fatalf("Se esperaban el encabezado y 2 áreas, pero se obtuvo: %v (%v)", rows, err)
+	}
+
+	if rows[1][1] != "Ventas" || rows[1][3] != "5" {
+		t.Errorf("Se esperaba Ventas con 5 personas, pero se obtuvo: %v", rows[1])
+	}
+}
+
+// TestLoginHandler prueba el inicio de sesión con credenciales válidas e inválidas
+func TestLoginHandler(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	handler := NewAuthHandler(&mockAuthService{})
+
+	router := gin.Default()
+	router.POST("/auth/login", handler.Login)
+
+	validReq, _ := http.NewRequest("POST", "/auth/login", bytes.NewBufferString(`{"username": "admin", "password": "secreto123"}`))
+	validReq.Header.Set("Content-Type", "application/json")
+	validRecorder := httptest.NewRecorder()
+
+	invalidReq, _ := http.NewRequest("POST", "/auth/login", bytes.NewBufferString(`{"username": "admin", "password": "otra"}`))
+	invalidReq.Header.Set("Content-Type", "application/json")
+	invalidRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(validRecorder, validReq)
+	router.ServeHTTP(invalidRecorder, invalidReq)
+
+	// Assert
+	if validRecorder.Code != http.StatusOK {
+		t.Errorf("Se esperaba status 200, pero se obtuvo: %d", validRecorder.Code)
+	}
+
+	var response struct {
+		Data model.TokenPair `json:"data"`
+	}
+	if err := json.Unmarshal(validRecorder.Body.Bytes(), &response); err != nil || response.Data.AccessToken != "token-valido" {
+		t.Errorf("Se esperaba el token de acceso, pero se obtuvo: %s", validRecorder.Body.String())
+	}
+
+	if invalidRecorder.Code != http.StatusUnauthorized {
+		t.Errorf("Se esperaba status 401, pero se obtuvo: %d", invalidRecorder.Code)
+	}
+}
+
+// TestRequireAuthMiddleware prueba que las rutas protegidas exijan un token de acceso válido
+func TestRequireAuthMiddleware(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	handler := NewAreaHandler(&mockAreaService{areas: []model.Area{}})
+
+	router := gin.Default()
+	areas := router.Group("/areas", RequireAuth(&mockAuthService{}, &mockAPIKeyService{}))
+	areas.GET("", handler.GetAll)
+
+	tests := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{"sin token", "", http.StatusUnauthorized},
+		{"esquema distinto", "Basic YWRtaW46c2VjcmV0bw==", http.StatusUnauthorized},
+		{"token inválido", "Bearer token-falso", http.StatusUnauthorized},
+		{"token válido", "Bearer token-valido", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/areas", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.expected {
+				t.Errorf("Se esperaba status %d, pero se obtuvo: %d", tt.expected, w.Code)
+			}
+
+			if tt.expected == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Errorf("Se esperaba el encabezado WWW-Authenticate")
+			}
+		})
+	}
+}
+
+// TestAuthorizeRoles prueba que cada ruta aplique la política de roles
+func TestAuthorizeRoles(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	handler := NewAreaHandler(&mockAreaService{areas: []model.Area{}})
+
+	router := gin.Default()
+	areas := router.Group("/areas", RequireAuth(&mockAuthService{}, &mockAPIKeyService{}))
+	areas.GET("", Authorize(authz.DefaultPolicy, authz.AreasRead), handler.GetAll)
+	areas.DELETE("/:id", Authorize(authz.DefaultPolicy, authz.AreasDelete), handler.Delete)
+
+	tests := []struct {
+		name     string
+		method   string
+		token    string
+		expected int
+	}{
+		{"viewer lista áreas", "GET", "token-viewer", http.StatusOK},
+		{"viewer no elimina áreas", "DELETE", "token-viewer", http.StatusForbidden},
+		{"area-manager no elimina áreas", "DELETE", "token-manager", http.StatusForbidden},
+		{"admin elimina áreas", "DELETE", "token-valido", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/areas"
+			if tt.method == "DELETE" {
+				url = "/areas/1"
+			}
+			req, _ := http.NewRequest(tt.method, url, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.expected {
+				t.Errorf("Se esperaba status %d, pero se obtuvo: %d", tt.expected, w.Code)
+			}
+		})
+	}
+}
+
+// TestAreaManagerScope prueba que un area-manager solo vea y edite personas de su área
+func TestAreaManagerScope(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	ownPersona := model.Persona{Nombre: "Ana García", Email: "ana@test.com", AreaID: 2}
+	ownPersona.ID = 1
+
+	otherPersona := model.Persona{Nombre: "Luis Díaz", Email: "luis@test.com", AreaID: 3}
+	otherPersona.ID = 2
+
+	mockService := &mockPersonaService{
+		personas:   []model.Persona{ownPersona, otherPersona},
+		shouldFail: false,
+	}
+
+	handler := NewPersonaHandler(mockService)
+
+	router := gin.Default()
+	personas := router.Group("/personas", RequireAuth(&mockAuthService{}, &mockAPIKeyService{}))
+	personas.GET("", Authorize(authz.DefaultPolicy, authz.PersonasRead), handler.GetAll)
+	personas.GET("/:id", Authorize(authz.DefaultPolicy, authz.PersonasRead), handler.GetByID)
+	personas.PUT("/:id", Authorize(authz.DefaultPolicy, authz.PersonasWrite), handler.Update)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer token-manager")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act
+	list := request("GET", "/personas", "")
+	listedQuery := mockService.lastQuery
+	otherArea := request("GET", "/personas?area_id=3", "")
+	own := request("GET", "/personas/1", "")
+	other := request("GET", "/personas/2", "")
+	moveOut := request("PUT", "/personas/1", `{"nombre": "Ana García", "email": "ana@test.com", "area_id": 3}`)
+	editOther := request("PUT", "/personas/2", `{"nombre": "Luis Díaz", "email": "luis@test.com", "area_id": 2}`)
+	editOwn := request("PUT", "/personas/1", `{"nombre": "Ana G.", "email": "ana@test.com", "area_id": 2}`)
+
+	// Assert
+	if list.Code != http.StatusOK || listedQuery.AreaID != 2 {
+		t.Errorf("Se esperaba el listado limitado al área 2, pero se obtuvo: %d %+v", list.Code, listedQuery)
+	}
+
+	if otherArea.Code != http.StatusForbidden {
+		t.Errorf("Se esperaba status 403 al filtrar por otra área, pero se obtuvo: %d", otherArea.Code)
+	}
+
+	if own.Code != http.StatusOK || other.Code != http.StatusNotFound {
+		t.Errorf("Se esperaba 200 para su área y 404 para otra, pero se obtuvo: %d y %d", own.Code, other.Code)
+	}
+
+	if moveOut.Code != http.StatusForbidden || editOther.Code != http.StatusNotFound {
+		t.Errorf("Se esperaba 403 al mover fuera del área y 404 al editar otra, pero se obtuvo: %d y %d", moveOut.Code, editOther.Code)
+	}
+
+	if editOwn.Code != http.StatusOK {
+		t.Errorf("Se esperaba status 200 al editar una persona de su área, pero se obtuvo: %d", editOwn.Code)
+	}
+}
+
+// TestRouteWithoutAuthorize prueba que una ruta de personas registrada sin Authorize rechace la
+// petición en lugar de darle a un area-manager acceso a todas las áreas
+func TestRouteWithoutAuthorize(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	otherPersona := model.Persona{Nombre: "Luis Díaz", Email: "luis@test.com", AreaID: 3}
+	otherPersona.ID = 1
+	mockService := &mockPersonaService{personas: []model.Persona{otherPersona}}
+	handler := NewPersonaHandler(mockService)
+
+	router := gin.New()
+	personas := router.Group("/personas", RequireAuth(&mockAuthService{}, &mockAPIKeyService{}))
+	personas.GET("", handler.GetAll)
+	personas.GET("/:id", handler.GetByID)
+	personas.POST("", handler.Create)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer token-manager")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act
+	list := request("GET", "/personas", "")
+	get := request("GET", "/personas/1", "")
+	create := request("POST", "/personas", `{"nombre": "Ana", "email": "ana@test.com", "area_id": 3}`)
+
+	// Assert
+	for name, w := range map[string]*httptest.ResponseRecorder{"listar": list, "obtener": get, "crear": create} {
+		if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), `"code":"internal_error"`) {
+			t.Errorf("Se esperaba status 500 al %s, pero se obtuvo: %d %s", name, w.Code, w.Body.String())
+		}
+	}
+	if len(mockService.personas) != 1 {
+		t.Errorf("Se esperaba que no se creara la persona, pero hay: %d", len(mockService.personas))
+	}
+}
+
+// TestAPIKeyAuthentication prueba que una API key se acepte en X-API-Key y solo autorice sus scopes
+func TestAPIKeyAuthentication(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	areaHandler := NewAreaHandler(&mockAreaService{areas: []model.Area{}})
+	personaHandler := NewPersonaHandler(&mockPersonaService{personas: []model.Persona{}})
+	apiKeyHandler := NewAPIKeyHandler(&mockAPIKeyService{})
+
+	router := gin.Default()
+	api := router.Group("", RequireAuth(&mockAuthService{}, &mockAPIKeyService{}))
+	api.GET("/personas", Authorize(authz.DefaultPolicy, authz.PersonasRead), personaHandler.GetAll)
+	api.GET("/areas", Authorize(authz.DefaultPolicy, authz.AreasRead), areaHandler.GetAll)
+	api.GET("/api-keys", Authorize(authz.DefaultPolicy, authz.APIKeysManage), apiKeyHandler.GetAll)
+
+	tests := []struct {
+		name     string
+		url      string
+		key      string
+		expected int
+	}{
+		{"scope otorgado", "/personas", "ak_lectura", http.StatusOK},
+		{"scope no otorgado", "/areas", "ak_lectura", http.StatusForbidden},
+		{"areas:write no incluye areas:read", "/areas", "ak_escritura", http.StatusForbidden},
+		{"una API key no administra API keys", "/api-keys", "ak_escritura", http.StatusForbidden},
+		{"API key desconocida", "/personas", "ak_falsa", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			req.Header.Set("X-API-Key", tt.key)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.expected {
+				t.Errorf("Se esperaba status %d, pero se obtuvo: %d", tt.expected, w.Code)
+			}
+		})
+	}
+}
+
+// TestCreateAPIKeyHandler prueba que la clave en claro solo se entregue al crearla
+func TestCreateAPIKeyHandler(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	handler := NewAPIKeyHandler(&mockAPIKeyService{})
+
+	router := gin.Default()
+	router.POST("/api-keys", RequireAuth(&mockAuthService{}, &mockAPIKeyService{}), handler.Create)
+
+	body := `{"name": "reportes", "scopes": ["personas:read"]}`
+	req, _ := http.NewRequest("POST", "/api-keys", bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer token-valido")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Se esperaba status 201, pero se obtuvo: %d", w.Code)
+	}
+
+	var response struct {
+		Key  string       `json:"key"`
+		Data model.APIKey `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	if response.Key != "ak_nueva-clave" || response.Data.CreatedBy != 1 {
+		t.Errorf("Se esperaba la clave en claro y el creador 1, pero se obtuvo: %+v", response)
+	}
+
+	if strings.Contains(w.Body.String(), "key_hash") {
+		t.Errorf("No se esperaba el hash de la clave en la respuesta")
+	}
+}
+
+// TestCreateUserHandler prueba que solo admin cree usuarios y que la respuesta no incluya el hash
+func TestCreateUserHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		token    string
+		body     string
+		expected int
+	}{
+		{"admin crea un area-manager", "token-valido", `{"username": "jefa", "password": "secreto123", "role": "area-manager", "area_id": 2}`, http.StatusCreated},
+		{"faltan datos", "token-valido", `{"username": "jefa"}`, http.StatusBadRequest},
+		{"un area-manager no crea usuarios", "token-manager", `{"username": "otro", "password": "secreto123", "role": "viewer"}`, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockUserService{}
+			router := gin.New()
+			router.POST("/users", RequireAuth(&mockAuthService{}, &mockAPIKeyService{}), Authorize(authz.DefaultPolicy, authz.UsersManage), NewUserHandler(mockService).Create)
+
+			req, _ := http.NewRequest("POST", "/users", bytes.NewBufferString(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.expected {
+				t.Fatalf("Se esperaba status %d, pero se obtuvo: %d", tt.expected, w.Code)
+			}
+			if tt.expected != http.StatusCreated {
+				if mockService.created != nil {
+					t.Errorf("Se esperaba que no se llamara al servicio")
+				}
+				return
+			}
+			if mockService.created.AreaID == nil || *mockService.created.AreaID != 2 || mockService.created.Role != model.RoleAreaManager {
+				t.Errorf("Se esperaba el rol y el área del cuerpo, pero se obtuvo: %+v", mockService.created)
+			}
+			if strings.Contains(w.Body.String(), "hash") || strings.Contains(w.Body.String(), "secreto123") {
+				t.Errorf("No se esperaba la contraseña ni su hash en la respuesta: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+// TestGetAuditHandler prueba los filtros del endpoint GET /audit
+func TestGetAuditHandler(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	entry := model.AuditEntry{ID: 1, Entity: model.AuditEntityPersona, EntityID: 3, Action: model.AuditUpdate, Actor: "admin",
+		Changes: model.AuditChanges{"area_id": {Before: 1, After: 2}}}
+	mockService := &mockAuditService{entries: []model.AuditEntry{entry}}
+	handler := NewAuditHandler(mockService, &mockPersonaService{})
+
+	router := gin.Default()
+	router.GET("/audit", handler.GetAll)
+
+	tests := []struct {
+		name     string
+		url      string
+		expected int
+	}{
+		{"filtro por persona", "/audit?entity=persona&id=3&action=update", http.StatusOK},
+		{"entidad desconocida", "/audit?entity=usuario", http.StatusBadRequest},
+		{"acción desconocida", "/audit?action=borrar", http.StatusBadRequest},
+		{"id inválido", "/audit?entity=area&id=abc", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.expected {
+				t.Errorf("Se esperaba status %d, pero se obtuvo: %d", tt.expected, w.Code)
+			}
+		})
+	}
+
+	expected := model.AuditQuery{Entity: model.AuditEntityPersona, EntityID: 3, Action: model.AuditUpdate}
+	if mockService.lastQuery.Entity != expected.Entity || mockService.lastQuery.EntityID != expected.EntityID || mockService.lastQuery.Action != expected.Action {
+		t.Errorf("Se esperaba la consulta %+v, pero se obtuvo: %+v", expected, mockService.lastQuery)
+	}
+}
+
+// TestPersonaHistoryHandler prueba el historial de una persona y el alcance de un area-manager
+func TestPersonaHistoryHandler(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	ownPersona := model.Persona{Nombre: "Ana García", Email: "ana@test.com", AreaID: 2}
+	ownPersona.ID = 1
+
+	otherPersona := model.Persona{Nombre: "Luis Díaz", Email: "luis@test.com", AreaID: 3}
+	otherPersona.ID = 2
+
+	mockService := &mockAuditService{entries: []model.AuditEntry{}}
+	handler := NewAuditHandler(mockService, &mockPersonaService{personas: []model.Persona{ownPersona, otherPersona}})
+
+	router := gin.Default()
+	personas := router.Group("/personas", RequireAuth(&mockAuthService{}, &mockAPIKeyService{}))
+	personas.GET("/:id/history", Authorize(authz.DefaultPolicy, authz.PersonasRead), handler.PersonaHistory)
+
+	request := func(url, token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", url, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act
+	admin := request("/personas/2/history", "token-valido")
+	adminQuery := mockService.lastQuery
+	own := request("/personas/1/history", "token-manager")
+	other := request("/personas/2/history", "token-manager")
+
+	// Assert
+	if admin.Code != http.StatusOK || adminQuery.Entity != model.AuditEntityPersona || adminQuery.EntityID != 2 {
+		t.Errorf("Se esperaba el historial de la persona 2, pero se obtuvo: %d %+v", admin.Code, adminQuery)
+	}
+
+	if own.Code != http.StatusOK {
+		t.Errorf("Se esperaba status 200 para una persona del área, pero se obtuvo: %d", own.Code)
+	}
+
+	if other.Code != http.StatusNotFound {
+		t.Errorf("Se esperaba status 404 para una persona de otra área, pero se obtuvo: %d", other.Code)
+	}
+}
+
+// Mock del servicio de salud
+type mockHealthService struct {
+	readiness *model.Readiness
+}
+
+func (m *mockHealthService) Ready(ctx context.Context) *model.Readiness {
+	return m.readiness
+}
+
+func (m *mockHealthService) Build() model.BuildInfo {
+	return model.BuildInfo{Version: "1.2.3", Commit: "abc123", StartedAt: time.Now().Add(-time.Minute)}
+}
+
+// TestHealthHandlers prueba las sondas de vida y disponibilidad con la base disponible y caída
+func TestHealthHandlers(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	degraded := &model.Readiness{
+		Status: model.HealthDegraded,
+		Checks: map[string]model.HealthCheck{
+			"database":   {Status: model.HealthFail, Error: "la base de datos no responde"},
+			"migrations": {Status: model.HealthFail, Error: "no se chequeó porque la base de datos no responde"},
+		},
+	}
+	healthy := &model.Readiness{
+		Status: model.HealthOK,
+		Checks: map[string]model.HealthCheck{"database": {Status: model.HealthOK}, "migrations": {Status: model.HealthOK}},
+		Pool:   model.PoolStats{OpenConnections: 5, InUse: 2, Idle: 3, WaitCount: 7},
+	}
+
+	tests := []struct {
+		name      string
+		readiness *model.Readiness
+		url       string
+		expected  int
+	}{
+		{"livez con la base caída", degraded, "/livez", http.StatusOK},
+		{"readyz disponible", healthy, "/readyz", http.StatusOK},
+		{"readyz con la base caída", degraded, "/readyz", http.StatusServiceUnavailable},
+		{"health disponible", healthy, "/health", http.StatusOK},
+		{"health con la base caída", degraded, "/health", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(&mockHealthService{readiness: tt.readiness})
+			router := gin.Default()
+			router.GET("/livez", handler.Live)
+			router.GET("/readyz", handler.Ready)
+			router.GET("/health", handler.Health)
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.expected {
+				t.Errorf("Se esperaba status %d, pero se obtuvo: %d", tt.expected, w.Code)
+			}
+
+			if tt.url == "/livez" {
+				return
+			}
+
+			var response struct {
+				Status model.HealthStatus           `json:"status"`
+				Checks map[string]model.HealthCheck `json:"checks"`
+				Build  *model.BuildInfo             `json:"build"`
+				Pool   map[string]int64             `json:"pool"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Error al decodificar respuesta: %v", err)
+			}
+
+			if response.Status != tt.readiness.Status || len(response.Checks) != 2 {
+				t.Errorf("Se esperaba el detalle de cada chequeo, pero se obtuvo: %s", w.Body.String())
+			}
+
+			if tt.url == "/health" && (response.Build == nil || response.Build.Version != "1.2.3" || response.Build.Commit != "abc123") {
+				t.Errorf("Se esperaba la información del binario, pero se obtuvo: %s", w.Body.String())
+			}
+
+			// El pool solo informa cantidades
+			expectedPool := map[string]int64{
+				"open_connections": int64(tt.readiness.Pool.OpenConnections),
+				"in_use":           int64(tt.readiness.Pool.InUse),
+				"idle":             int64(tt.readiness.Pool.Idle),
+				"wait_count":       tt.readiness.Pool.WaitCount,
+			}
+			if !reflect.DeepEqual(response.Pool, expectedPool) {
+				t.Errorf("Se esperaba el pool %v, pero se obtuvo: %s", expectedPool, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestDeadlineMiddleware prueba que el plazo de la petición llegue al servicio y se responda 504 o 503
+func TestDeadlineMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		routes       map[string]time.Duration
+		handler      gin.HandlerFunc
+		cancel       bool
+		expected     int
+		expectedCode string
+	}{
+		{
+			name:         "Plazo vencido en el servicio",
+			timeout:      20 * time.Millisecond,
+			handler:      NewPersonaHandler(&mockPersonaService{blockUntilDone: true}).GetAll,
+			expected:     http.StatusGatewayTimeout,
+			expectedCode: "request_timeout",
+		},
+		{
+			name:    "Handler que termina sin responder",
+			timeout: 20 * time.Millisecond,
+			handler: func(c *gin.Context) {
+				<-c.Request.Context().Done()
+			},
+			expected:     http.StatusGatewayTimeout,
+			expectedCode: "request_timeout",
+		},
+		{
+			name:         "Petición cancelada",
+			timeout:      time.Second,
+			handler:      NewPersonaHandler(&mockPersonaService{blockUntilDone: true}).GetAll,
+			cancel:       true,
+			expected:     http.StatusServiceUnavailable,
+			expectedCode: "request_canceled",
+		},
+		{
+			name:     "Petición a tiempo",
+			timeout:  time.Second,
+			handler:  NewPersonaHandler(&mockPersonaService{}).GetAll,
+			expected: http.StatusOK,
+		},
+		{
+			name: "Sin plazo",
+			handler: func(c *gin.Context) {
+				if _, ok := c.Request.Context().Deadline(); ok {
+					c.Status(http.StatusInternalServerError)
+					return
+				}
+				c.Status(http.StatusOK)
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name:    "Ruta con plazo propio",
+			timeout: 20 * time.Millisecond,
+			routes:  map[string]time.Duration{"/personas": time.Second},
+			handler: func(c *gin.Context) {
+				select {
+				case <-time.After(100 * time.Millisecond):
+					c.Status(http.StatusOK)
+				case <-c.Request.Context().Done():
+				}
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name:    "Ruta sin plazo",
+			timeout: 20 * time.Millisecond,
+			routes:  map[string]time.Duration{"/personas": 0},
+			handler: func(c *gin.Context) {
+				if _, ok := c.Request.Context().Deadline(); ok {
+					c.Status(http.StatusInternalServerError)
+					return
+				}
+				c.Status(http.StatusOK)
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name:         "Otra ruta con plazo propio",
+			timeout:      20 * time.Millisecond,
+			routes:       map[string]time.Duration{"/personas/export": time.Second},
+			handler:      NewPersonaHandler(&mockPersonaService{blockUntilDone: true}).GetAll,
+			expected:     http.StatusGatewayTimeout,
+			expectedCode: "request_timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := gin.New()
+			router.GET("/personas", Deadline(tt.timeout, tt.routes), unrestricted, tt.handler)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+			req, _ := http.NewRequestWithContext(ctx, "GET", "/personas", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.expected {
+				t.Errorf("Se esperaba status %d, pero se obtuvo: %d", tt.expected, w.Code)
+			}
+			if tt.expectedCode != "" && !strings.Contains(w.Body.String(), `"code":"`+tt.expectedCode+`"`) {
+				t.Errorf("Se esperaba el código %s, pero se obtuvo: %s", tt.expectedCode, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestDeadlineExtendsWriteTimeout prueba que una ruta con plazo propio pueda escribir después del
+// plazo de escritura del servidor y que el resto de las rutas lo conserve
+func TestDeadlineExtendsWriteTimeout(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Deadline(time.Second, map[string]time.Duration{"/export": 5 * time.Second}))
+	slow := func(c *gin.Context) {
+		time.Sleep(300 * time.Millisecond)
+		c.String(http.StatusOK, "filas")
+	}
+	router.GET("/export", slow)
+	router.GET("/personas", slow)
+
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	// Act
+	exportResp, exportErr := http.Get(server.URL + "/export")
+	_, personasErr := http.Get(server.URL + "/personas")
+
+	// Assert
+	if exportErr != nil {
+		t.Fatalf("Se esperaba la respuesta de la exportación, pero se obtuvo: %v", exportErr)
+	}
+	defer exportResp.Body.Close()
+	if body, _ := io.ReadAll(exportResp.Body); exportResp.StatusCode != http.StatusOK || string(body) != "filas" {
+		t.Errorf("Se esperaba status 200 con las filas, pero se obtuvo: %d %s", exportResp.StatusCode, body)
+	}
+	if personasErr == nil {
+		t.Errorf("Se esperaba que el plazo de escritura cortara la respuesta de las otras rutas")
+	}
+}
+
+// TestMovePersonasHandler prueba el endpoint POST /areas/:id/move-personas
+func TestMovePersonasHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		url      string
+		expected int
+	}{
+		{"Mueve las personas", "/areas/1/move-personas?to=2", http.StatusOK},
+		{"Sin destino", "/areas/1/move-personas", http.StatusBadRequest},
+		{"Destino inválido", "/areas/1/move-personas?to=abc", http.StatusBadRequest},
+		{"Misma área", "/areas/1/move-personas?to=1", http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAreaHandler(&mockAreaService{})
+			router := gin.New()
+			router.POST("/areas/:id/move-personas", handler.MovePersonas)
+
+			req, _ := http.NewRequest("POST", tt.url, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.expected {
+				t.Errorf("Se esperaba status %d, pero se obtuvo: %d", tt.expected, w.Code)
+			}
+			if tt.expected == http.StatusOK && !strings.Contains(w.Body.String(), `"personas_movidas":2`) {
+				t.Errorf("Se esperaba la cantidad de personas movidas, pero se obtuvo: %s", w.Body.String())
+			}
+		})
 	}
 }
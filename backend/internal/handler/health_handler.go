@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceName identifica al backend en la respuesta de /health y en las trazas
+const ServiceName = "backend-monolito"
+
+type HealthHandler struct {
+	service service.HealthService
+}
+
+func NewHealthHandler(service service.HealthService) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+// Live responde si el proceso está vivo; no chequea dependencias para que una caída de la
+// base no provoque reinicios del servidor
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": model.HealthOK})
+}
+
+// Ready responde 200 si el servicio puede atender peticiones y 503 con el detalle de cada
+// chequeo si no
+func (h *HealthHandler) Ready(c *gin.Context) {
+	readiness := h.service.Ready(c.Request.Context())
+	c.JSON(readinessStatus(readiness), readiness)
+}
+
+// Health combina la disponibilidad con la información del binario
+func (h *HealthHandler) Health(c *gin.Context) {
+	readiness := h.service.Ready(c.Request.Context())
+	build := h.service.Build()
+	c.JSON(readinessStatus(readiness), gin.H{
+		"status":  readiness.Status,
+		"service": ServiceName,
+		"build":   build,
+		"uptime":  time.Since(build.StartedAt).Round(time.Second).String(),
+		"checks":  readiness.Checks,
+		"pool":    readiness.Pool,
+	})
+}
+
+func readinessStatus(readiness *model.Readiness) int {
+	if readiness.Ready() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
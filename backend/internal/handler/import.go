@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importRowJSON es una fila de importación enviada como JSON
+type importRowJSON struct {
+	Nombre string `json:"nombre"`
+	Email  string `json:"email"`
+	AreaID uint   `json:"area_id"`
+	Area   string `json:"area"`
+}
+
+// maxImportRowBytes es el tamaño máximo previsto de una fila: nombre y email de hasta 200
+// caracteres, que en UTF-8 pueden ocupar el doble, más el área y el formato
+const maxImportRowBytes = 1024
+
+// maxImportBodyBytes limita el cuerpo de una importación antes de leerlo, para que una
+// petición no pueda ocupar memoria sin límite antes de contar sus filas
+const maxImportBodyBytes = int64(service.MaxImportRows+1) * maxImportRowBytes
+
+// Import crea personas en lote a partir de un CSV (text/csv) o un arreglo JSON.
+// Con ?mode=best_effort se crean las filas válidas; por defecto no se crea ninguna si alguna falla.
+// Con ?dry_run=true solo se validan las filas.
+func (h *PersonaHandler) Import(c *gin.Context) {
+	opts, err := parseImportOptions(c)
+	if err != nil {
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", err)
+		return
+	}
+
+	decision, ok := accessDecision(c, "Error al importar las personas")
+	if !ok || !requireUnrestricted(c, decision, "Error al importar las personas") {
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodyBytes)
+
+	var rows []model.ImportRow
+	switch mediaType {
+	case "text/csv":
+		rows, err = parseImportCSV(body)
+	case "application/json":
+		rows, err = parseImportJSON(body)
+	default:
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "El cuerpo debe ser text/csv o application/json",
+			"code":  codeUnsupportedMediaType,
+		})
+		return
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": fmt.Sprintf("El cuerpo supera el máximo de %d bytes por importación", tooLarge.Limit),
+			"code":  codeRequestTooLarge,
+		})
+		return
+	}
+	if err != nil {
+		respondBadRequest(c, codeInvalidBody, "Datos inválidos", err)
+		return
+	}
+
+	report, err := h.service.Import(c.Request.Context(), rows, opts)
+	if err != nil {
+		respondError(c, err, "Error al importar las personas")
+		return
+	}
+
+	status := http.StatusOK
+	switch {
+	case report.DryRun:
+	case report.Mode == model.ImportAtomic && report.Failed > 0:
+		status = http.StatusUnprocessableEntity
+	case report.Created > 0:
+		status = http.StatusCreated
+	}
+
+	c.JSON(status, gin.H{
+		"message": importMessage(report),
+		"data":    report,
+	})
+}
+
+// parseImportOptions lee los parámetros mode y dry_run
+func parseImportOptions(c *gin.Context) (model.ImportOptions, error) {
+	opts := model.ImportOptions{Mode: model.ImportAtomic}
+
+	switch mode := model.ImportMode(c.Query("mode")); mode {
+	case "", model.ImportAtomic:
+	case model.ImportBestEffort:
+		opts.Mode = mode
+	default:
+		return opts, errors.New("mode debe ser atomic o best_effort")
+	}
+
+	if raw := c.Query("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, errors.New("dry_run debe ser true o false")
+		}
+		opts.DryRun = dryRun
+	}
+
+	return opts, nil
+}
+
+// importMessage resume el resultado de la importación para el cliente
+func importMessage(report *model.ImportReport) string {
+	switch {
+	case report.DryRun:
+		return fmt.Sprintf("Validación completada: %d de %d filas válidas", report.Valid, report.Total)
+	case report.Mode == model.ImportAtomic && report.Failed > 0:
+		return "Importación cancelada: hay filas con errores y no se creó ninguna persona"
+	}
+	return fmt.Sprintf("Importación completada: %d de %d personas creadas", report.Created, report.Total)
+}
+
+// parseImportCSV lee un CSV con encabezado. Las columnas reconocidas son nombre, email y
+// area_id o area (nombre del área); las demás se ignoran.
+func parseImportCSV(body io.Reader) ([]model.ImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("el CSV está vacío")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"nombre", "email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("falta la columna '%s' en el encabezado", required)
+		}
+	}
+	_, hasAreaID := columns["area_id"]
+	_, hasArea := columns["area"]
+	if !hasAreaID && !hasArea {
+		return nil, errors.New("el encabezado debe incluir la columna 'area_id' o 'area'")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []model.ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := model.ImportRow{
+			Line: line,
+			Persona: model.Persona{
+				Nombre: field(record, "nombre"),
+				Email:  field(record, "email"),
+			},
+			AreaNombre: field(record, "area"),
+		}
+
+		if raw := field(record, "area_id"); raw != "" {
+			areaID, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				row.ParseError = fmt.Sprintf("area_id '%s' no es un número válido", raw)
+			}
+			row.Persona.AreaID = uint(areaID)
+		}
+
+		rows = append(rows, row)
+		if len(rows) > service.MaxImportRows {
+			return nil, fmt.Errorf("se aceptan como máximo %d filas por importación", service.MaxImportRows)
+		}
+	}
+	return rows, nil
+}
+
+// parseImportJSON lee un arreglo JSON de personas; la línea de cada fila es su posición en el arreglo
+func parseImportJSON(body io.Reader) ([]model.ImportRow, error) {
+	var items []importRowJSON
+	if err := json.NewDecoder(body).Decode(&items); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, err
+		}
+		return nil, errors.New("el cuerpo debe ser un arreglo JSON de personas")
+	}
+	if len(items) > service.MaxImportRows {
+		return nil, fmt.Errorf("se aceptan como máximo %d filas por importación", service.MaxImportRows)
+	}
+
+	rows := make([]model.ImportRow, len(items))
+	for i, item := range items {
+		rows[i] = model.ImportRow{
+			Line: i + 1,
+			Persona: model.Persona{
+				Nombre: strings.TrimSpace(item.Nombre),
+				Email:  strings.TrimSpace(item.Email),
+				AreaID: item.AreaID,
+			},
+			AreaNombre: strings.TrimSpace(item.Area),
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"backend/internal/service"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// bindMergePatch lee el cuerpo de un PATCH y verifica que sea un objeto JSON.
+// Si no lo es, responde 415 o 400 y retorna false.
+func bindMergePatch(c *gin.Context) (map[string]interface{}, bool) {
+	mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil || (mediaType != "application/merge-patch+json" && mediaType != "application/json") {
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "El cuerpo debe ser application/merge-patch+json",
+			"code":  codeUnsupportedMediaType,
+		})
+		return nil, false
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondBadRequest(c, codeInvalidBody, "Datos inválidos", err)
+		return nil, false
+	}
+
+	var patch map[string]interface{}
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		respondBadRequest(c, codeInvalidBody, "Datos inválidos", errors.New("el documento de merge patch debe ser un objeto JSON"))
+		return nil, false
+	}
+	return patch, true
+}
+
+// applyMergePatch aplica un JSON Merge Patch (RFC 7396) sobre la representación JSON de
+// current y decodifica el resultado en dst. Solo se aceptan los campos listados en editable.
+func applyMergePatch(current interface{}, patch map[string]interface{}, editable map[string]bool, dst interface{}) error {
+	for field := range patch {
+		if !editable[field] {
+			return service.NewValidationError("field_not_editable", field, fmt.Sprintf("el campo '%s' no se puede modificar", field))
+		}
+	}
+
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(mergeValue(document, patch))
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	if err := decoder.Decode(dst); err != nil {
+		return service.NewValidationError("invalid_patch", "", fmt.Sprintf("el documento resultante no es válido: %v", err))
+	}
+
+	if err := binding.Validator.ValidateStruct(dst); err != nil {
+		return service.NewValidationError("invalid_patch", "", err.Error())
+	}
+	return nil
+}
+
+// mergeValue implementa el algoritmo MergePatch de la RFC 7396
+func mergeValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+	return targetObject
+}
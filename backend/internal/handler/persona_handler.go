@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"backend/internal/authz"
 	"backend/internal/model"
 	"backend/internal/service"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,59 +23,96 @@ func NewPersonaHandler(service service.PersonaService) *PersonaHandler {
 // Create crea una nueva persona
 func (h *PersonaHandler) Create(c *gin.Context) {
 	var persona model.Persona
-	
+
 	if err := c.ShouldBindJSON(&persona); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Datos inválidos",
-			"details": err.Error(),
-		})
+		respondBadRequest(c, codeInvalidBody, "Datos inválidos", err)
+		return
+	}
+
+	decision, ok := accessDecision(c, "Error al registrar la persona")
+	if !ok || !requireArea(c, decision, persona.AreaID, "Error al registrar la persona") {
 		return
 	}
 
-	if err := h.service.Create(&persona); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Error al registrar la persona",
-			"details": err.Error(),
-		})
+	if err := h.service.Create(c.Request.Context(), &persona); err != nil {
+		respondError(c, err, "Error al registrar la persona")
 		return
 	}
 
+	setETag(c, persona.Version)
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Persona registrada exitosamente",
 		"data":    persona,
 	})
 }
 
-// GetAll obtiene todas las personas
+// GetAll obtiene las personas de forma paginada, con filtros y ordenamiento
 func (h *PersonaHandler) GetAll(c *gin.Context) {
-	personas, err := h.service.GetAll()
+	query, err := parsePersonaQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Error al obtener las personas",
-		})
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": personas,
-	})
+	if !scopePersonaQuery(c, &query, "Error al obtener las personas") {
+		return
+	}
+
+	personas, total, err := h.service.GetAll(c.Request.Context(), query)
+	if err != nil {
+		respondError(c, err, "Error al obtener las personas")
+		return
+	}
+
+	c.JSON(http.StatusOK, paginatedResponse(c, personas, query.ListOptions, total))
+}
+
+// parsePersonaQuery lee los filtros area_id, nombre y email_domain junto con la paginación
+func parsePersonaQuery(c *gin.Context) (model.PersonaQuery, error) {
+	opts, err := parseListOptions(c, model.PersonaSortFields)
+	if err != nil {
+		return model.PersonaQuery{}, err
+	}
+
+	query := model.PersonaQuery{
+		ListOptions: opts,
+		Nombre:      strings.TrimSpace(c.Query("nombre")),
+		EmailDomain: strings.TrimPrefix(strings.TrimSpace(c.Query("email_domain")), "@"),
+	}
+
+	if raw := c.Query("area_id"); raw != "" {
+		areaID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return model.PersonaQuery{}, errors.New("area_id inválido")
+		}
+		query.AreaID = uint(areaID)
+	}
+
+	return query, nil
 }
 
 // GetByID obtiene una persona por ID
 func (h *PersonaHandler) GetByID(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID inválido",
-		})
+	id, ok := parseID(c)
+	if !ok {
 		return
 	}
 
-	persona, err := h.service.GetByID(uint(id))
+	decision, ok := accessDecision(c, "Error al obtener la persona")
+	if !ok {
+		return
+	}
+
+	persona, err := h.service.GetByID(c.Request.Context(), id)
+	if err == nil && !decision.Permits(persona.AreaID) {
+		err = errPersonaOutOfScope()
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Persona no encontrada",
-		})
+		respondError(c, err, "Error al obtener la persona")
+		return
+	}
+
+	if notModified(c, persona.Version) {
 		return
 	}
 
@@ -85,11 +125,21 @@ func (h *PersonaHandler) GetByID(c *gin.Context) {
 func (h *PersonaHandler) GetByEmail(c *gin.Context) {
 	email := c.Param("email")
 
-	persona, err := h.service.GetByEmail(email)
+	decision, ok := accessDecision(c, "Error al obtener la persona")
+	if !ok {
+		return
+	}
+
+	persona, err := h.service.GetByEmail(c.Request.Context(), email)
+	if err == nil && !decision.Permits(persona.AreaID) {
+		err = errPersonaOutOfScope()
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Persona no encontrada",
-		})
+		respondError(c, err, "Error al obtener la persona")
+		return
+	}
+
+	if notModified(c, persona.Version) {
 		return
 	}
 
@@ -100,52 +150,136 @@ func (h *PersonaHandler) GetByEmail(c *gin.Context) {
 
 // Update actualiza una persona
 func (h *PersonaHandler) Update(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID inválido",
-		})
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
 	var persona model.Persona
 	if err := c.ShouldBindJSON(&persona); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Datos inválidos",
-			"details": err.Error(),
-		})
+		respondBadRequest(c, codeInvalidBody, "Datos inválidos", err)
 		return
 	}
 
-	if err := h.service.Update(uint(id), &persona); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Error al actualizar la persona",
-			"details": err.Error(),
-		})
+	decision, ok := accessDecision(c, "Error al actualizar la persona")
+	if !ok || !requireArea(c, decision, persona.AreaID, "Error al actualizar la persona") || !h.authorizePersona(c, decision, id, "Error al actualizar la persona") {
+		return
+	}
+
+	if version != 0 {
+		persona.Version = version
+	}
+
+	if err := h.service.Update(c.Request.Context(), id, &persona); err != nil {
+		respondError(c, err, "Error al actualizar la persona")
 		return
 	}
 
+	setETag(c, persona.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Persona actualizada exitosamente",
 		"data":    persona,
 	})
 }
 
-// Delete elimina una persona
+// personaPatchFields son los campos de una persona que se pueden modificar con PATCH
+var personaPatchFields = map[string]bool{
+	"nombre":  true,
+	"email":   true,
+	"area_id": true,
+}
+
+// Patch actualiza parcialmente una persona con un documento JSON Merge Patch (RFC 7396)
+func (h *PersonaHandler) Patch(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	patch, ok := bindMergePatch(c)
+	if !ok {
+		return
+	}
+
+	decision, ok := accessDecision(c, "Error al actualizar la persona")
+	if !ok {
+		return
+	}
+	persona, err := h.service.GetByID(c.Request.Context(), id)
+	if err == nil && !decision.Permits(persona.AreaID) {
+		err = errPersonaOutOfScope()
+	}
+	if err != nil {
+		respondError(c, err, "Error al actualizar la persona")
+		return
+	}
+
+	var patched model.Persona
+	if err := applyMergePatch(persona, patch, personaPatchFields, &patched); err != nil {
+		respondError(c, err, "Error al actualizar la persona")
+		return
+	}
+
+	if !requireArea(c, decision, patched.AreaID, "Error al actualizar la persona") {
+		return
+	}
+
+	if version != 0 {
+		patched.Version = version
+	}
+
+	if err := h.service.Update(c.Request.Context(), id, &patched); err != nil {
+		respondError(c, err, "Error al actualizar la persona")
+		return
+	}
+
+	setETag(c, patched.Version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Persona actualizada exitosamente",
+		"data":    patched,
+	})
+}
+
+// Delete elimina una persona. Con ?hard=true la eliminación es definitiva.
 func (h *PersonaHandler) Delete(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	hard, err := parseHard(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID inválido",
-		})
+		respondBadRequest(c, codeInvalidQuery, "Parámetros inválidos", err)
 		return
 	}
 
-	if err := h.service.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Error al eliminar la persona",
-			"details": err.Error(),
-		})
+	decision, ok := accessDecision(c, "Error al eliminar la persona")
+	if !ok || !h.authorizePersona(c, decision, id, "Error al eliminar la persona") {
+		return
+	}
+
+	if hard {
+		err = h.service.Purge(c.Request.Context(), id, version)
+	} else {
+		err = h.service.Delete(c.Request.Context(), id, version)
+	}
+	if err != nil {
+		respondError(c, err, "Error al eliminar la persona")
 		return
 	}
 
@@ -153,3 +287,68 @@ func (h *PersonaHandler) Delete(c *gin.Context) {
 		"message": "Persona eliminada exitosamente",
 	})
 }
+
+// Restore revierte la eliminación de una persona
+func (h *PersonaHandler) Restore(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	decision, ok := accessDecision(c, "Error al restaurar la persona")
+	if !ok || !requireUnrestricted(c, decision, "Error al restaurar la persona") {
+		return
+	}
+
+	persona, err := h.service.Restore(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Error al restaurar la persona")
+		return
+	}
+
+	setETag(c, persona.Version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Persona restaurada exitosamente",
+		"data":    persona,
+	})
+}
+
+// scopePersonaQuery limita el listado al área del usuario cuando su rol solo le da acceso a ella.
+// Si se pidió otra área responde 403 y retorna false.
+func scopePersonaQuery(c *gin.Context, query *model.PersonaQuery, message string) bool {
+	decision, ok := accessDecision(c, message)
+	if !ok {
+		return false
+	}
+	if !decision.Restricted() {
+		return true
+	}
+	if query.AreaID != 0 && !requireArea(c, decision, query.AreaID, message) {
+		return false
+	}
+	query.AreaID = decision.AreaID
+	return true
+}
+
+// authorizePersona verifica que la decisión alcance a la persona indicada. Si no la alcanza
+// responde 404, igual que si no existiera, y retorna false.
+func (h *PersonaHandler) authorizePersona(c *gin.Context, decision authz.Decision, id uint, message string) bool {
+	if !decision.Restricted() {
+		return true
+	}
+
+	persona, err := h.service.GetByID(c.Request.Context(), id)
+	if err == nil && !decision.Permits(persona.AreaID) {
+		err = errPersonaOutOfScope()
+	}
+	if err != nil {
+		respondError(c, err, message)
+		return false
+	}
+	return true
+}
+
+// errPersonaOutOfScope es el error para una persona fuera del alcance del usuario; se informa como inexistente
+func errPersonaOutOfScope() *service.Error {
+	return service.NewNotFoundError("persona_not_found", "persona no encontrada")
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"backend/internal/model"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseListOptions lee los parámetros page, page_size, deleted y sort de la query string
+func parseListOptions(c *gin.Context, allowedSort map[string]bool) (model.ListOptions, error) {
+	var opts model.ListOptions
+
+	if raw := c.Query("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return opts, fmt.Errorf("page debe ser un entero mayor que 0")
+		}
+		opts.Page = page
+	}
+
+	if raw := c.Query("page_size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 || size > model.MaxPageSize {
+			return opts, fmt.Errorf("page_size debe ser un entero entre 1 y %d", model.MaxPageSize)
+		}
+		opts.PageSize = size
+	}
+
+	switch deleted := c.Query("deleted"); deleted {
+	case "", "exclude":
+	case "include":
+		opts.Deleted = model.DeletedInclude
+	case "only":
+		opts.Deleted = model.DeletedOnly
+	default:
+		return opts, fmt.Errorf("deleted debe ser exclude, include u only")
+	}
+
+	sort, err := parseSort(c.Query("sort"), allowedSort)
+	if err != nil {
+		return opts, err
+	}
+	opts.Sort = sort
+
+	opts.Normalize()
+	return opts, nil
+}
+
+// parseSort interpreta una lista como "nombre,-created_at", donde el prefijo "-" indica orden descendente
+func parseSort(raw string, allowed map[string]bool) ([]model.SortField, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var fields []model.SortField
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		field := model.SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !allowed[field.Field] {
+			return nil, fmt.Errorf("no se puede ordenar por '%s'", field.Field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// paginatedResponse arma la respuesta de un listado con el total y los enlaces a las páginas vecinas
+func paginatedResponse(c *gin.Context, data interface{}, opts model.ListOptions, total int64) gin.H {
+	totalPages := int((total + int64(opts.PageSize) - 1) / int64(opts.PageSize))
+
+	links := gin.H{"next": nil, "prev": nil}
+	if opts.Page < totalPages {
+		links["next"] = pageLink(c, opts.Page+1, opts.PageSize)
+	}
+	if opts.Page > 1 {
+		links["prev"] = pageLink(c, min(opts.Page-1, max(totalPages, 1)), opts.PageSize)
+	}
+
+	return gin.H{
+		"data": data,
+		"meta": gin.H{
+			"total":       total,
+			"page":        opts.Page,
+			"page_size":   opts.PageSize,
+			"total_pages": totalPages,
+		},
+		"links": links,
+	}
+}
+
+// pageLink construye la URL de la página indicada conservando los demás parámetros
+func pageLink(c *gin.Context, page, pageSize int) string {
+	u := *c.Request.URL
+	q := u.Query()
+	q.Set("page", strconv.Itoa(page))
+	q.Set("page_size", strconv.Itoa(pageSize))
+	u.RawQuery = q.Encode()
+	return u.RequestURI()
+}
+
+// parseID lee el parámetro :id de la ruta; si no es válido responde 400 y retorna false
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondBadRequest(c, codeInvalidID, "ID inválido", nil)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseHard lee el parámetro hard de la query string
+func parseHard(c *gin.Context) (bool, error) {
+	raw := c.Query("hard")
+	if raw == "" {
+		return false, nil
+	}
+
+	hard, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("hard debe ser true o false")
+	}
+	return hard, nil
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery responde 500 cuando un handler entra en pánico y lo registra con slog. Un pánico
+// con http.ErrAbortHandler se propaga para que net/http corte la conexión sin terminar la
+// respuesta, como cuando una exportación falla después de enviar filas.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, err any) {
+		if err == http.ErrAbortHandler {
+			panic(err)
+		}
+		slog.ErrorContext(c.Request.Context(), "pánico en un handler", "error", err, "stack", string(debug.Stack()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "Error interno del servidor",
+			"code":  codeInternal,
+		})
+	})
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserHandler struct {
+	service service.UserService
+}
+
+func NewUserHandler(service service.UserService) *UserHandler {
+	return &UserHandler{service: service}
+}
+
+// Create crea un usuario con su rol y, para area-manager, su área
+func (h *UserHandler) Create(c *gin.Context) {
+	var req model.UserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, codeInvalidBody, "Datos inválidos", err)
+		return
+	}
+
+	user, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "Error al crear el usuario")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Usuario creado exitosamente",
+		"data":    user,
+	})
+}
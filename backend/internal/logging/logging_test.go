@@ -0,0 +1,228 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// decodeLines convierte la salida JSON del logger en un mapa por línea
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Se esperaba una línea JSON, pero se obtuvo: %q (%v)", line, err)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		level   string
+		wantErr bool
+	}{
+		{name: "JSON", format: "json", level: "info"},
+		{name: "Texto", format: "text", level: "debug"},
+		{name: "Formato desconocido", format: "xml", level: "info", wantErr: true},
+		{name: "Nivel desconocido", format: "json", level: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := New(&bytes.Buffer{}, tt.format, tt.level)
+
+			// Assert
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Se esperaba error = %v, pero se obtuvo: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestContextHandler(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger, _ := New(&buf, "json", "info")
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x0a, 0xf7, 0x65, 0x19},
+		SpanID:  trace.SpanID{0xb7, 0xad, 0x6b, 0x71},
+	})
+	ctx := trace.ContextWithSpanContext(WithRequestID(context.Background(), "abc-123"), spanContext)
+
+	// Act
+	logger.InfoContext(ctx, "mensaje")
+	logger.Info("sin contexto")
+
+	// Assert
+	lines := decodeLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("Se esperaban 2 líneas, pero se obtuvo: %d", len(lines))
+	}
+	if lines[0]["request_id"] != "abc-123" {
+		t.Errorf("Se esperaba request_id abc-123, pero se obtuvo: %v", lines[0]["request_id"])
+	}
+	if lines[0]["trace_id"] != spanContext.TraceID().String() {
+		t.Errorf("Se esperaba trace_id %s, pero se obtuvo: %v", spanContext.TraceID(), lines[0]["trace_id"])
+	}
+	if lines[0]["span_id"] != spanContext.SpanID().String() {
+		t.Errorf("Se esperaba span_id %s, pero se obtuvo: %v", spanContext.SpanID(), lines[0]["span_id"])
+	}
+	if _, ok := lines[1]["request_id"]; ok {
+		t.Errorf("No se esperaba request_id sin contexto, pero se obtuvo: %v", lines[1]["request_id"])
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		requestID string
+		status    int
+		wantID    string
+		wantLevel string
+	}{
+		{name: "Genera un ID si no se recibe", status: http.StatusOK, wantLevel: "INFO"},
+		{name: "Propaga el ID recibido", requestID: "req-42.a:b_c", status: http.StatusOK, wantID: "req-42.a:b_c", wantLevel: "INFO"},
+		{name: "Reemplaza un ID inválido", requestID: "id con espacios\n", status: http.StatusOK, wantLevel: "INFO"},
+		{name: "Error del cliente", status: http.StatusNotFound, wantLevel: "WARN"},
+		{name: "Error del servidor", status: http.StatusInternalServerError, wantLevel: "ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			logger, _ := New(&buf, "json", "info")
+			var handlerID string
+			router := gin.New()
+			router.Use(Middleware(logger))
+			router.GET("/personas/:id", func(c *gin.Context) {
+				handlerID = RequestID(c.Request.Context())
+				c.Status(tt.status)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/personas/7", nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			id := w.Header().Get(RequestIDHeader)
+			if tt.wantID != "" && id != tt.wantID {
+				t.Errorf("Se esperaba el ID %q, pero se obtuvo: %q", tt.wantID, id)
+			}
+			if tt.wantID == "" && len(id) != 32 {
+				t.Errorf("Se esperaba un ID generado de 32 caracteres, pero se obtuvo: %q", id)
+			}
+			if handlerID != id {
+				t.Errorf("Se esperaba el ID %q en el contexto, pero se obtuvo: %q", id, handlerID)
+			}
+
+			lines := decodeLines(t, &buf)
+			if len(lines) != 1 {
+				t.Fatalf("Se esperaba 1 línea, pero se obtuvo: %d", len(lines))
+			}
+			line := lines[0]
+			if line["level"] != tt.wantLevel {
+				t.Errorf("Se esperaba el nivel %s, pero se obtuvo: %v", tt.wantLevel, line["level"])
+			}
+			if line["request_id"] != id {
+				t.Errorf("Se esperaba request_id %q, pero se obtuvo: %v", id, line["request_id"])
+			}
+			if line["route"] != "/personas/:id" || line["path"] != "/personas/7" {
+				t.Errorf("Se esperaba la ruta /personas/:id y el path /personas/7, pero se obtuvo: %v y %v", line["route"], line["path"])
+			}
+			if line["status"] != float64(tt.status) {
+				t.Errorf("Se esperaba status %d, pero se obtuvo: %v", tt.status, line["status"])
+			}
+		})
+	}
+}
+
+func TestGormLogger(t *testing.T) {
+	query := func() (string, int64) {
+		return `SELECT * FROM "personas" WHERE email = $1`, 1
+	}
+
+	tests := []struct {
+		name      string
+		level     string
+		elapsed   time.Duration
+		err       error
+		wantLevel string
+	}{
+		{name: "Consulta normal con nivel debug", level: "debug", elapsed: time.Millisecond, wantLevel: "DEBUG"},
+		{name: "Consulta normal con nivel info", level: "info", elapsed: time.Millisecond},
+		{name: "Consulta lenta", level: "info", elapsed: time.Second, wantLevel: "WARN"},
+		{name: "Consulta fallida", level: "info", elapsed: time.Millisecond, err: errors.New("conexión cerrada"), wantLevel: "ERROR"},
+		{name: "Registro no encontrado", level: "info", elapsed: time.Millisecond, err: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			logger, _ := New(&buf, "json", tt.level)
+			gormLogger := NewGormLogger(logger, 200*time.Millisecond)
+			ctx := WithRequestID(context.Background(), "abc-123")
+
+			// Act
+			gormLogger.Trace(ctx, time.Now().Add(-tt.elapsed), query, tt.err)
+
+			// Assert
+			lines := decodeLines(t, &buf)
+			if tt.wantLevel == "" {
+				if len(lines) != 0 {
+					t.Errorf("No se esperaban líneas, pero se obtuvo: %v", lines)
+				}
+				return
+			}
+			if len(lines) != 1 {
+				t.Fatalf("Se esperaba 1 línea, pero se obtuvo: %d", len(lines))
+			}
+			if lines[0]["level"] != tt.wantLevel {
+				t.Errorf("Se esperaba el nivel %s, pero se obtuvo: %v", tt.wantLevel, lines[0]["level"])
+			}
+			if lines[0]["request_id"] != "abc-123" {
+				t.Errorf("Se esperaba request_id abc-123, pero se obtuvo: %v", lines[0]["request_id"])
+			}
+		})
+	}
+}
+
+func TestGormLoggerParamsFilter(t *testing.T) {
+	// Arrange
+	gormLogger := NewGormLogger(nil, 0)
+
+	// Act
+	sql, params := gormLogger.ParamsFilter(context.Background(), "SELECT 1 WHERE email = $1", "ana@example.com")
+
+	// Assert
+	if sql != "SELECT 1 WHERE email = $1" || params != nil {
+		t.Errorf("Se esperaba el SQL sin parámetros, pero se obtuvo: %q %v", sql, params)
+	}
+}
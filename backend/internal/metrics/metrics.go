@@ -0,0 +1,141 @@
+// Package metrics expone métricas en el formato de texto de Prometheus: peticiones HTTP por
+// ruta, el pool de conexiones a la base de datos y métricas del negocio.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace es el prefijo de las métricas propias del backend
+const namespace = "backend"
+
+// unmatchedRoute agrupa las peticiones que no coinciden con ninguna ruta, para que las URLs
+// arbitrarias no creen una serie por cada una
+const unmatchedRoute = "unmatched"
+
+// Metrics reúne los colectores del backend en un registro propio
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+// New crea el registro con las métricas HTTP y las del proceso y el runtime de Go
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Peticiones HTTP atendidas, por método, ruta y status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duración de las peticiones HTTP, por método, ruta y status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Peticiones HTTP en curso.",
+		}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.inFlight,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Middleware mide cada petición. La ruta es la plantilla registrada en Gin, como
+// /api/v1/personas/:id, y no la URL, para mantener acotada la cantidad de series.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		m.requests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.duration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler sirve las métricas. Si un colector falla se sirven las demás.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
+
+// RegisterDB agrega las métricas del pool de conexiones (go_sql_*) de la base dbName
+func (m *Metrics) RegisterDB(db *sql.DB, dbName string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// AreaConteoSource obtiene las áreas con su cantidad de personas; service.AreaService lo implementa
+type AreaConteoSource interface {
+	GetAreasConConteo(ctx context.Context) ([]model.AreaConConteo, error)
+}
+
+// RegisterAreaConteo agrega la cantidad de personas por área. Se consulta en cada lectura de
+// las métricas con la misma consulta de GET /areas/conteo; timeout limita su duración para que
+// una base lenta no deje colgada la lectura de /metrics.
+func (m *Metrics) RegisterAreaConteo(source AreaConteoSource, timeout time.Duration) {
+	m.registry.MustRegister(&areaConteoCollector{
+		source:  source,
+		timeout: timeout,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "personas_por_area"),
+			"Personas vigentes en cada área vigente.",
+			[]string{"area"}, nil,
+		),
+	})
+}
+
+// areaConteoCollector convierte el conteo de personas por área en un gauge por área
+type areaConteoCollector struct {
+	source  AreaConteoSource
+	timeout time.Duration
+	desc    *prometheus.Desc
+}
+
+func (c *areaConteoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *areaConteoCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	areas, err := c.source.GetAreasConConteo(ctx)
+	if err != nil {
+		slog.Warn("error al obtener el conteo de personas por área para las métricas", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for _, area := range areas {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(area.Personas), area.Nombre)
+	}
+}
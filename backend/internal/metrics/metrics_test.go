@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"backend/internal/model"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Mock de la fuente del conteo de personas por área
+type mockAreaConteoSource struct {
+	areas []model.AreaConConteo
+	err   error
+	delay time.Duration
+}
+
+func (m *mockAreaConteoSource) GetAreasConConteo(ctx context.Context) ([]model.AreaConConteo, error) {
+	select {
+	case <-time.After(m.delay):
+		return m.areas, m.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// scrape lee las métricas expuestas por el router
+func scrape(t *testing.T, router *gin.Engine) string {
+	t.Helper()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Se esperaba status 200 en /metrics, pero se obtuvo: %d", w.Code)
+	}
+	body, _ := io.ReadAll(w.Body)
+	return string(body)
+}
+
+// newRouter crea un router con el middleware de métricas y algunas rutas de ejemplo
+func newRouter(m *Metrics) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(m.Middleware())
+	router.GET("/metrics", gin.WrapH(m.Handler()))
+	router.GET("/personas/:id", func(c *gin.Context) {
+		if c.Param("id") == "0" {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+// TestMiddlewareLabelsByRouteTemplate prueba que las peticiones se cuenten por la plantilla de la ruta
+func TestMiddlewareLabelsByRouteTemplate(t *testing.T) {
+	// Arrange
+	m := New()
+	router := newRouter(m)
+
+	// Act
+	for _, url := range []string{"/personas/1", "/personas/2", "/personas/0", "/no-existe/123"} {
+		req, _ := http.NewRequest("GET", url, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	body := scrape(t, router)
+
+	// Assert
+	expected := []string{
+		`backend_http_requests_total{method="GET",route="/personas/:id",status="200"} 2`,
+		`backend_http_requests_total{method="GET",route="/personas/:id",status="404"} 1`,
+		`backend_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`backend_http_request_duration_seconds_count{method="GET",route="/personas/:id",status="200"} 2`,
+		`backend_http_requests_in_flight 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("Se esperaba la línea '%s', pero se obtuvo:\n%s", line, body)
+		}
+	}
+
+	if strings.Contains(body, "/personas/1") {
+		t.Errorf("Se esperaba que la URL no se usara como etiqueta")
+	}
+}
+
+// TestRegisterAreaConteo prueba el gauge de personas por área y que un error no impida servir el resto
+func TestRegisterAreaConteo(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   *mockAreaConteoSource
+		expected string
+	}{
+		{
+			"conteo disponible",
+			&mockAreaConteoSource{areas: []model.AreaConConteo{{Nombre: "Ventas", Personas: 5}, {Nombre: "Marketing", Personas: 0}}},
+			`backend_personas_por_area{area="Ventas"} 5`,
+		},
+		{
+			"error en la consulta",
+			&mockAreaConteoSource{err: errors.New("connection refused")},
+			`backend_http_requests_in_flight`,
+		},
+		{
+			"consulta lenta",
+			&mockAreaConteoSource{areas: []model.AreaConConteo{{Nombre: "Ventas", Personas: 5}}, delay: time.Minute},
+			`backend_http_requests_in_flight`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			m := New()
+			m.RegisterAreaConteo(tt.source, 50*time.Millisecond)
+			router := newRouter(m)
+
+			// Act
+			start := time.Now()
+			body := scrape(t, router)
+
+			// Assert
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Se esperaba que la consulta se cortara en su plazo, pero la lectura tardó: %s", elapsed)
+			}
+			if !strings.Contains(body, tt.expected) {
+				t.Errorf("Se esperaba '%s', pero se obtuvo:\n%s", tt.expected, body)
+			}
+		})
+	}
+}
+
+// TestRegisterDB prueba que se expongan las estadísticas del pool de conexiones
+func TestRegisterDB(t *testing.T) {
+	// Arrange
+	m := New()
+	// sql.Open no se conecta: alcanza para leer las estadísticas del pool vacío
+	db, err := sql.Open("pgx", "postgres://localhost/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	m.RegisterDB(db, "app_db")
+	router := newRouter(m)
+
+	// Act
+	body := scrape(t, router)
+
+	// Assert
+	if !strings.Contains(body, `go_sql_open_connections{db_name="app_db"} 0`) {
+		t.Errorf("Se esperaban las métricas del pool, pero se obtuvo:\n%s", body)
+	}
+}
@@ -0,0 +1,322 @@
+// Package migrate aplica las migraciones versionadas del esquema de la base de datos.
+// Cada migración es un par de archivos NNNN_nombre.up.sql y NNNN_nombre.down.sql en
+// migrations/; las versiones aplicadas se registran en la tabla schema_migrations.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var embedded embed.FS
+
+// lockID identifica el advisory lock que impide que dos procesos migren a la vez
+const lockID = 72_011_015
+
+// ErrNotMigrated indica que la base no está en la última versión conocida por el binario
+var ErrNotMigrated = errors.New("la base de datos no está migrada")
+
+// Migration es una versión del esquema con el SQL para aplicarla y revertirla
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status es el estado de una migración en la base
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load lee las migraciones de fsys y las ordena por versión.
+// Cada versión debe tener su archivo up y su archivo down.
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, file := range files {
+		base := path.Base(file)
+		stem, direction, ok := cutDirection(base)
+		if !ok {
+			return nil, fmt.Errorf("la migración %s debe terminar en .up.sql o .down.sql", base)
+		}
+
+		rawVersion, name, ok := strings.Cut(stem, "_")
+		version, err := strconv.ParseUint(rawVersion, 10, 32)
+		if !ok || err != nil || version == 0 || name == "" {
+			return nil, fmt.Errorf("la migración %s debe llamarse NNNN_nombre", base)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[uint(version)]
+		if !exists {
+			migration = &Migration{Version: uint(version), Name: name}
+			byVersion[uint(version)] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("la versión %d tiene dos nombres: %s y %s", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("la migración %04d_%s necesita sus archivos up y down", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// cutDirection separa "0001_nombre.up.sql" en "0001_nombre" y "up"
+func cutDirection(file string) (string, string, bool) {
+	for _, direction := range []string{"up", "down"} {
+		if stem, ok := strings.CutSuffix(file, "."+direction+".sql"); ok {
+			return stem, direction, true
+		}
+	}
+	return "", "", false
+}
+
+// Migrator aplica y revierte migraciones sobre una base PostgreSQL
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New crea un Migrator con las migraciones incluidas en el binario
+func New(db *sql.DB) (*Migrator, error) {
+	sub, err := fs.Sub(embedded, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := Load(sub)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest retorna la última versión conocida, o 0 si no hay migraciones
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up aplica todas las migraciones pendientes en orden, cada una en su propia transacción,
+// y retorna las que aplicó
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+			err := inTx(ctx, conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("migración %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down revierte las últimas steps migraciones aplicadas y retorna las que revirtió
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := versions[migration.Version]; !ok {
+				continue
+			}
+			err := inTx(ctx, conn, migration.Down,
+				"DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+			if err != nil {
+				return fmt.Errorf("migración %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status retorna cada migración conocida con la fecha en que se aplicó, o nil si está pendiente
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	versions, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		status[i] = Status{Migration: migration}
+		if appliedAt, ok := versions[migration.Version]; ok {
+			status[i].AppliedAt = &appliedAt
+		}
+	}
+	return status, nil
+}
+
+// Check verifica que la base tenga aplicadas exactamente las migraciones conocidas por el binario.
+// Retorna un error que envuelve ErrNotMigrated si faltan migraciones o si la base tiene
+// versiones que el binario no conoce.
+func (m *Migrator) Check(ctx context.Context) error {
+	var exists bool
+	err := m.db.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: no existe la tabla schema_migrations", ErrNotMigrated)
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	known := map[uint]bool{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+
+	applied := 0
+	for rows.Next() {
+		var version uint
+		if err := rows.Scan(&version); err != nil {
+			return err
+		}
+		if !known[version] {
+			return fmt.Errorf("%w: la base tiene la versión %d, que este binario no conoce", ErrNotMigrated, version)
+		}
+		applied++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if pending := len(m.migrations) - applied; pending > 0 {
+		return fmt.Errorf("%w: hay %d migraciones pendientes", ErrNotMigrated, pending)
+	}
+	return nil
+}
+
+// withLock ejecuta fn en una conexión que tiene el advisory lock de las migraciones
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// ensureTable crea la tabla schema_migrations si no existe
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`)
+	return err
+}
+
+// appliedVersions obtiene las versiones aplicadas con su fecha
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[uint]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := map[uint]time.Time{}
+	for rows.Next() {
+		var version uint
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		versions[version] = appliedAt
+	}
+	return versions, rows.Err()
+}
+
+// inTx ejecuta el SQL de una migración y el registro de su versión en una sola transacción
+func inTx(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,152 @@
+package migrate_test
+
+import (
+	"backend/internal/migrate"
+	"backend/internal/testdb"
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+)
+
+// Este archivo usa un paquete de test externo porque testdb importa migrate
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
+
+// openMigrator abre la base de testdb, que ya está migrada, y su migrador. Al terminar el test la
+// base se vuelve a migrar, para que los demás paquetes la encuentren al día aunque el test falle.
+func openMigrator(t *testing.T) (*sql.DB, *migrate.Migrator) {
+	t.Helper()
+	db, err := sql.Open("pgx", testdb.DSN(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := migrate.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if _, err := migrator.Up(context.Background()); err != nil {
+			t.Errorf("Se esperaba dejar la base migrada, pero se obtuvo: %v", err)
+		}
+		db.Close()
+	})
+	return db, migrator
+}
+
+// TestMigratorPostgres recorre el ciclo de las migraciones sobre un Postgres real: la base que
+// prepara testdb ya está migrada; se revierte la última, luego todas, y se vuelven a aplicar
+// desde dos migradores a la vez, que el advisory lock debe turnar
+func TestMigratorPostgres(t *testing.T) {
+	// Arrange
+	db, migrator := openMigrator(t)
+	ctx := context.Background()
+	total := int(migrator.Latest())
+
+	// Act y Assert: la base está al día
+	if err := migrator.Check(ctx); err != nil {
+		t.Fatalf("Se esperaba la base migrada, pero se obtuvo: %v", err)
+	}
+	status, err := migrator.Status(ctx)
+	if err != nil || len(status) != total || status[total-1].AppliedAt == nil {
+		t.Fatalf("Se esperaban %d migraciones aplicadas, pero se obtuvo: %+v (%v)", total, status, err)
+	}
+
+	// Act y Assert: revertir la última deja la base sin migrar
+	reverted, err := migrator.Down(ctx, 1)
+	if err != nil || len(reverted) != 1 || reverted[0].Version != migrator.Latest() {
+		t.Fatalf("Se esperaba revertir la versión %d, pero se obtuvo: %+v (%v)", migrator.Latest(), reverted, err)
+	}
+	if err := migrator.Check(ctx); !errors.Is(err, migrate.ErrNotMigrated) {
+		t.Errorf("Se esperaba ErrNotMigrated, pero se obtuvo: %v", err)
+	}
+	status, err = migrator.Status(ctx)
+	if err != nil || status[total-1].AppliedAt != nil || status[total-2].AppliedAt == nil {
+		t.Errorf("Se esperaba solo la última migración pendiente, pero se obtuvo: %+v (%v)", status, err)
+	}
+
+	// Act y Assert: los scripts down del resto también funcionan
+	reverted, err = migrator.Down(ctx, total)
+	if err != nil || len(reverted) != total-1 {
+		t.Fatalf("Se esperaba revertir %d migraciones, pero se obtuvo: %d (%v)", total-1, len(reverted), err)
+	}
+
+	// Act: dos procesos aplican las migraciones a la vez
+	var (
+		wg      sync.WaitGroup
+		applied = make([][]migrate.Migration, 2)
+		errs    = make([]error, 2)
+	)
+	for i := range applied {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			other, err := migrate.New(db)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			applied[i], errs[i] = other.Up(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	// Assert
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Se esperaba que ambos migradores terminaran sin error, pero se obtuvo: %v", err)
+		}
+	}
+	if len(applied[0])+len(applied[1]) != total {
+		t.Errorf("Se esperaba que entre ambos aplicaran %d migraciones una sola vez, pero se obtuvo: %d y %d", total, len(applied[0]), len(applied[1]))
+	}
+	if err := migrator.Check(ctx); err != nil {
+		t.Errorf("Se esperaba la base migrada otra vez, pero se obtuvo: %v", err)
+	}
+}
+
+// TestMigratorAdoptsInitDBSchema prueba que las migraciones adopten una base creada con el antiguo
+// init_db.sql, sin la columna version ni los índices parciales, y conserven sus datos
+func TestMigratorAdoptsInitDBSchema(t *testing.T) {
+	// Arrange
+	db, migrator := openMigrator(t)
+	ctx := context.Background()
+	if _, err := migrator.Down(ctx, int(migrator.Latest())); err != nil {
+		t.Fatal(err)
+	}
+	script, err := os.ReadFile("testdata/init_db.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, string(script)); err != nil {
+		t.Fatalf("Se esperaba cargar el esquema de init_db.sql, pero se obtuvo: %v", err)
+	}
+
+	// Act
+	applied, err := migrator.Up(ctx)
+
+	// Assert
+	if err != nil || len(applied) != int(migrator.Latest()) {
+		t.Fatalf("Se esperaba aplicar todas las migraciones, pero se obtuvo: %d (%v)", len(applied), err)
+	}
+	if err := migrator.Check(ctx); err != nil {
+		t.Errorf("Se esperaba la base migrada, pero se obtuvo: %v", err)
+	}
+
+	var personas int64
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM personas").Scan(&personas); err != nil || personas != 30 {
+		t.Errorf("Se esperaba conservar las 30 personas, pero se obtuvo: %d (%v)", personas, err)
+	}
+
+	// Las actualizaciones con versión deben funcionar sobre las tablas adoptadas
+	var areaVersion, personaVersion int64
+	if err := db.QueryRowContext(ctx, "UPDATE areas SET version = version + 1 WHERE id = 1 RETURNING version").Scan(&areaVersion); err != nil || areaVersion != 2 {
+		t.Errorf("Se esperaba la versión 2 del área, pero se obtuvo: %d (%v)", areaVersion, err)
+	}
+	if err := db.QueryRowContext(ctx, "UPDATE personas SET version = version + 1 WHERE email = 'juan.perez@example.com' RETURNING version").Scan(&personaVersion); err != nil || personaVersion != 2 {
+		t.Errorf("Se esperaba la versión 2 de la persona, pero se obtuvo: %d (%v)", personaVersion, err)
+	}
+}
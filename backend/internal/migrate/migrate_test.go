@@ -0,0 +1,84 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestLoadSortsMigrations prueba que las migraciones se lean en orden de versión con su up y su down
+func TestLoadSortsMigrations(t *testing.T) {
+	// Arrange
+	fsys := fstest.MapFS{
+		"0002_agregar_indices.up.sql":   {Data: []byte("CREATE INDEX b;")},
+		"0002_agregar_indices.down.sql": {Data: []byte("DROP INDEX b;")},
+		"0001_crear_tablas.up.sql":      {Data: []byte("CREATE TABLE a;")},
+		"0001_crear_tablas.down.sql":    {Data: []byte("DROP TABLE a;")},
+	}
+
+	// Act
+	migrations, err := Load(fsys)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if len(migrations) != 2 || migrations[0].Version != 1 || migrations[1].Version != 2 {
+		t.Fatalf("Se esperaban las versiones 1 y 2 en orden, pero se obtuvo: %+v", migrations)
+	}
+
+	if migrations[0].Name != "crear_tablas" || migrations[0].Up != "CREATE TABLE a;" || migrations[0].Down != "DROP TABLE a;" {
+		t.Errorf("Se esperaba la migración crear_tablas completa, pero se obtuvo: %+v", migrations[0])
+	}
+}
+
+// TestLoadRejectsInvalidFiles prueba que se rechacen migraciones incompletas o mal nombradas
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		expected string
+	}{
+		{"sin down", fstest.MapFS{"0001_crear.up.sql": {Data: []byte("SELECT 1;")}}, "up y down"},
+		{"sin versión", fstest.MapFS{"crear.up.sql": {Data: []byte("SELECT 1;")}}, "NNNN_nombre"},
+		{"sin dirección", fstest.MapFS{"0001_crear.sql": {Data: []byte("SELECT 1;")}}, ".up.sql"},
+		{"nombres distintos", fstest.MapFS{
+			"0001_crear.up.sql":  {Data: []byte("SELECT 1;")},
+			"0001_otra.down.sql": {Data: []byte("SELECT 1;")},
+		}, "dos nombres"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := Load(tt.fsys)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Se esperaba un error con '%s', pero se obtuvo: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestEmbeddedMigrations prueba que las migraciones incluidas en el binario sean válidas y consecutivas
+func TestEmbeddedMigrations(t *testing.T) {
+	// Act
+	migrator, err := New(nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	for i, migration := range migrator.migrations {
+		if migration.Version != uint(i+1) {
+			t.Errorf("Se esperaba la versión %d, pero se obtuvo: %d", i+1, migration.Version)
+		}
+	}
+
+	if migrator.Latest() != uint(len(migrator.migrations)) {
+		t.Errorf("Se esperaba la última versión %d, pero se obtuvo: %d", len(migrator.migrations), migrator.Latest())
+	}
+}
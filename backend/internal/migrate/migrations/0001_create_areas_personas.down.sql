@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS personas;
+DROP TABLE IF EXISTS areas;
@@ -0,0 +1,49 @@
+-- Áreas y personas. Las sentencias toleran una base creada antes de las migraciones
+-- con init_db.sql o AutoMigrate, de modo que esta migración también la adopta.
+
+CREATE TABLE IF NOT EXISTS areas (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    nombre VARCHAR(100) NOT NULL,
+    descripcion TEXT,
+    version BIGINT NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS personas (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    nombre VARCHAR(200) NOT NULL,
+    email VARCHAR(200) NOT NULL,
+    area_id INTEGER NOT NULL,
+    version BIGINT NOT NULL DEFAULT 1
+);
+
+-- Las tablas de init_db.sql no tienen la columna version, que CREATE TABLE IF NOT EXISTS no agrega
+ALTER TABLE areas ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE personas ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+-- AutoMigrate creaba la clave foránea con el nombre fk_personas_area
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname IN ('fk_area', 'fk_personas_area')) THEN
+        ALTER TABLE personas ADD CONSTRAINT fk_area FOREIGN KEY (area_id) REFERENCES areas(id);
+    END IF;
+END $$;
+
+-- La unicidad de email y nombre solo considera los registros vigentes
+ALTER TABLE personas DROP CONSTRAINT IF EXISTS personas_email_key;
+ALTER TABLE personas DROP CONSTRAINT IF EXISTS uni_personas_email;
+ALTER TABLE areas DROP CONSTRAINT IF EXISTS areas_nombre_key;
+ALTER TABLE areas DROP CONSTRAINT IF EXISTS uni_areas_nombre;
+
+CREATE INDEX IF NOT EXISTS idx_personas_email ON personas(email);
+CREATE INDEX IF NOT EXISTS idx_personas_area_id ON personas(area_id);
+CREATE INDEX IF NOT EXISTS idx_personas_deleted_at ON personas(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_areas_nombre ON areas(nombre);
+CREATE INDEX IF NOT EXISTS idx_areas_deleted_at ON areas(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_personas_email_live ON personas(email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_areas_nombre_live ON areas(nombre) WHERE deleted_at IS NULL;
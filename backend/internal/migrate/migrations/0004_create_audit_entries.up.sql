@@ -0,0 +1,16 @@
+-- Auditoría de las operaciones sobre áreas y personas: autor y valores anteriores y nuevos
+
+CREATE TABLE IF NOT EXISTS audit_entries (
+    id SERIAL PRIMARY KEY,
+    entity VARCHAR(20) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(150) NOT NULL,
+    user_id INTEGER,
+    api_key_id INTEGER,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_entries_entity ON audit_entries(entity, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_entries_created_at ON audit_entries(created_at);
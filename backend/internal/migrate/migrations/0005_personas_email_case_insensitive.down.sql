@@ -0,0 +1,6 @@
+-- Los emails quedan normalizados; solo se restauran los índices anteriores
+
+DROP INDEX IF EXISTS idx_personas_email_live;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_personas_email_live ON personas(email) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_personas_email ON personas(email);
@@ -0,0 +1,11 @@
+-- Los emails de las personas se comparan sin distinguir mayúsculas: se guardan sin espacios y en
+-- minúsculas, y la unicidad entre las personas vigentes se verifica sobre LOWER(email).
+-- Si dos personas vigentes tienen emails que solo difieren en mayúsculas la migración falla
+-- sin aplicar cambios; se debe eliminar o corregir una de ellas antes de volver a ejecutarla.
+
+DROP INDEX IF EXISTS idx_personas_email_live;
+DROP INDEX IF EXISTS idx_personas_email;
+
+UPDATE personas SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email));
+
+CREATE UNIQUE INDEX idx_personas_email_live ON personas (LOWER(email)) WHERE deleted_at IS NULL;
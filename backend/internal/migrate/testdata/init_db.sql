@@ -1,3 +1,6 @@
+-- Esquema y datos de scripts/init_db.sql antes de las migraciones versionadas, para probar que
+-- la migración 0001 adopta una base creada con ese script
+
 -- Script de inicialización de base de datos - Monolito
 -- Base de datos: app_db
 
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScopeList es la lista de scopes de una API key; se guarda como texto separado por espacios
+type ScopeList []string
+
+// Value implementa driver.Valuer
+func (s ScopeList) Value() (driver.Value, error) {
+	return strings.Join(s, " "), nil
+}
+
+// Scan implementa sql.Scanner
+func (s *ScopeList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		*s = strings.Fields(v)
+	case []byte:
+		*s = strings.Fields(string(v))
+	case nil:
+		*s = nil
+	default:
+		return fmt.Errorf("no se puede leer %T como lista de scopes", value)
+	}
+	return nil
+}
+
+// APIKey es una clave para integraciones entre sistemas. Solo se guarda el hash de la clave;
+// Prefix son sus primeros caracteres y permite reconocerla en el listado.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primarykey"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	Prefix     string     `json:"prefix" gorm:"type:varchar(16);not null"`
+	KeyHash    string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Scopes     ScopeList  `json:"scopes" gorm:"type:text;not null"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedBy  uint       `json:"created_by" gorm:"not null"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName especifica el nombre de la tabla en la base de datos
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// APIKeyRequest son los datos para crear una API key. Sin ExpiresAt la clave vence en
+// DefaultAPIKeyTTL.
+type APIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// DefaultAPIKeyTTL es la vigencia de una API key creada sin fecha de expiración
+const DefaultAPIKeyTTL = 90 * 24 * time.Hour
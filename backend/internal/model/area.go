@@ -7,8 +7,12 @@ import (
 // Area representa el modelo de área de trabajo en el sistema
 type Area struct {
 	gorm.Model
-	Nombre      string `json:"nombre" gorm:"type:varchar(100);not null;unique" binding:"required"`
+	Nombre      string `json:"nombre" gorm:"type:varchar(100);not null;uniqueIndex:idx_areas_nombre_live,where:deleted_at IS NULL" binding:"required"`
 	Descripcion string `json:"descripcion" gorm:"type:text"`
+	// Version se incrementa en cada actualización y se expone como ETag
+	Version uint `json:"version" gorm:"not null;default:1"`
+	// Personas solo se completa cuando el listado se pide con include=conteo
+	Personas *int64 `json:"personas,omitempty" gorm:"->;-:migration"`
 }
 
 // TableName especifica el nombre de la tabla en la base de datos
@@ -16,6 +20,14 @@ func (Area) TableName() string {
 	return "areas"
 }
 
+// AuditFields retorna los campos editables que se registran en la auditoría
+func (a *Area) AuditFields() map[string]interface{} {
+	return map[string]interface{}{
+		"nombre":      a.Nombre,
+		"descripcion": a.Descripcion,
+	}
+}
+
 // AreaConConteo representa un área con el conteo de personas
 type AreaConConteo struct {
 	ID          uint   `json:"id"`
@@ -23,3 +35,13 @@ type AreaConConteo struct {
 	Descripcion string `json:"descripcion"`
 	Personas    int64  `json:"personas"`
 }
+
+// AreaDeleteOptions define qué hacer con las personas asignadas al eliminar un área.
+// Sin opciones, la eliminación se rechaza si el área todavía tiene personas.
+// Con Hard el área se elimina definitivamente y las opciones alcanzan también
+// a las personas eliminadas que la referencian.
+type AreaDeleteOptions struct {
+	ReassignTo uint
+	Cascade    bool
+	Hard       bool
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditEntity es el tipo de registro al que se refiere una entrada de auditoría
+type AuditEntity string
+
+const (
+	AuditEntityArea    AuditEntity = "area"
+	AuditEntityPersona AuditEntity = "persona"
+)
+
+// AuditAction es la operación registrada en una entrada de auditoría
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditPurge   AuditAction = "purge"
+	AuditRestore AuditAction = "restore"
+)
+
+// AuditChange es el valor de un campo antes y después de la operación.
+// Before es nil al crear o restaurar y After es nil al eliminar.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges son los campos modificados por una operación; se guarda como JSON
+type AuditChanges map[string]AuditChange
+
+// Value implementa driver.Valuer
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	raw, err := json.Marshal(c)
+	return string(raw), err
+}
+
+// Scan implementa sql.Scanner
+func (c *AuditChanges) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	case nil:
+		*c = nil
+		return nil
+	default:
+		return fmt.Errorf("no se puede leer %T como cambios de auditoría", value)
+	}
+	return json.Unmarshal(raw, c)
+}
+
+// AuditEntry registra quién realizó una operación sobre un área o una persona, cuándo y qué cambió.
+// Actor es el nombre del usuario o de la API key; las entradas no se modifican ni se eliminan.
+type AuditEntry struct {
+	ID        uint         `json:"id" gorm:"primarykey"`
+	Entity    AuditEntity  `json:"entity" gorm:"type:varchar(20);not null;index:idx_audit_entries_entity"`
+	EntityID  uint         `json:"entity_id" gorm:"not null;index:idx_audit_entries_entity"`
+	Action    AuditAction  `json:"action" gorm:"type:varchar(20);not null"`
+	Actor     string       `json:"actor" gorm:"type:varchar(150);not null"`
+	UserID    *uint        `json:"user_id,omitempty"`
+	APIKeyID  *uint        `json:"api_key_id,omitempty"`
+	Changes   AuditChanges `json:"changes" gorm:"type:jsonb;not null"`
+	CreatedAt time.Time    `json:"created_at" gorm:"index"`
+}
+
+// TableName especifica el nombre de la tabla en la base de datos
+func (AuditEntry) TableName() string {
+	return "audit_entries"
+}
+
+// AuditQuery contiene los filtros y la paginación del listado de auditoría
+type AuditQuery struct {
+	ListOptions
+	Entity   AuditEntity
+	EntityID uint
+	Action   AuditAction
+	Actor    string
+}
+
+// NewAuditChanges compara los campos auditados antes y después de una operación y retorna
+// los que cambiaron. Un lado nil representa un registro que no existía o que se eliminó.
+func NewAuditChanges(before, after map[string]interface{}) AuditChanges {
+	changes := AuditChanges{}
+	for field, value := range before {
+		if next, ok := after[field]; !ok || next != value {
+			changes[field] = AuditChange{Before: value, After: after[field]}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = AuditChange{After: value}
+		}
+	}
+	return changes
+}
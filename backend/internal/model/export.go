@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// ExportFormat es el formato de archivo de una exportación
+type ExportFormat string
+
+const (
+	ExportCSV   ExportFormat = "csv"
+	ExportXLSX  ExportFormat = "xlsx"
+	ExportJSONL ExportFormat = "jsonl"
+)
+
+// PersonaExport es una fila de la exportación de personas, con el nombre de su área
+type PersonaExport struct {
+	ID         uint      `json:"id"`
+	Nombre     string    `json:"nombre"`
+	Email      string    `json:"email"`
+	AreaID     uint      `json:"area_id"`
+	AreaNombre string    `json:"area"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
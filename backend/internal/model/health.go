@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// HealthStatus es el estado de un chequeo o del servicio completo
+type HealthStatus string
+
+const (
+	HealthOK       HealthStatus = "ok"
+	HealthDegraded HealthStatus = "degraded"
+	HealthFail     HealthStatus = "fail"
+)
+
+// BuildInfo identifica el binario que está corriendo
+type BuildInfo struct {
+	Version   string    `json:"version"`
+	Commit    string    `json:"commit"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// HealthCheck es el resultado de un chequeo de una dependencia
+type HealthCheck struct {
+	Status     HealthStatus `json:"status"`
+	DurationMs int64        `json:"duration_ms"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// PoolStats resume el uso del pool de conexiones a la base de datos; solo incluye cantidades,
+// las mismas que expone /metrics en go_sql_*
+type PoolStats struct {
+	OpenConnections int   `json:"open_connections"`
+	InUse           int   `json:"in_use"`
+	Idle            int   `json:"idle"`
+	WaitCount       int64 `json:"wait_count"`
+}
+
+// Readiness indica si el servicio puede atender peticiones: Status es ok solo si todos los
+// chequeos pasaron
+type Readiness struct {
+	Status HealthStatus           `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+	Pool   PoolStats              `json:"pool"`
+}
+
+// Ready indica si todos los chequeos pasaron
+func (r *Readiness) Ready() bool {
+	return r.Status == HealthOK
+}
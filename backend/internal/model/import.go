@@ -0,0 +1,56 @@
+package model
+
+// ImportMode indica cómo se comporta una importación cuando hay filas con errores
+type ImportMode string
+
+const (
+	// ImportAtomic importa todas las filas en una transacción o ninguna
+	ImportAtomic ImportMode = "atomic"
+	// ImportBestEffort importa las filas válidas y reporta las que fallan
+	ImportBestEffort ImportMode = "best_effort"
+)
+
+// ImportOptions contiene el modo de una importación y si solo se deben validar las filas
+type ImportOptions struct {
+	Mode   ImportMode
+	DryRun bool
+}
+
+// ImportRow es una fila leída del archivo de importación.
+// El área se puede indicar por ID o por nombre; ParseError registra un error de lectura de la fila.
+type ImportRow struct {
+	Line       int
+	Persona    Persona
+	AreaNombre string
+	ParseError string
+}
+
+// Estados posibles de una fila en el reporte de importación
+const (
+	ImportRowCreated = "created"
+	ImportRowValid   = "valid"
+	ImportRowSkipped = "skipped"
+	ImportRowFailed  = "error"
+)
+
+// ImportRowResult es el resultado de una fila en el reporte de importación
+type ImportRowResult struct {
+	Line   int    `json:"line"`
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	ID     uint   `json:"id,omitempty"`
+	Code   string `json:"code,omitempty"`
+	Field  string `json:"field,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportReport resume el resultado de una importación de personas
+type ImportReport struct {
+	Mode    ImportMode        `json:"mode"`
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
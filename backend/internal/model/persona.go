@@ -1,6 +1,8 @@
 package model
 
 import (
+	"strings"
+
 	"gorm.io/gorm"
 )
 
@@ -8,12 +10,29 @@ import (
 type Persona struct {
 	gorm.Model
 	Nombre string `json:"nombre" gorm:"type:varchar(200);not null" binding:"required"`
-	Email  string `json:"email" gorm:"type:varchar(200);not null;unique" binding:"required,email"`
+	Email  string `json:"email" gorm:"type:varchar(200);not null;uniqueIndex:idx_personas_email_live,expression:LOWER(email),where:deleted_at IS NULL" binding:"required,email"`
 	AreaID uint   `json:"area_id" gorm:"not null" binding:"required"`
-	Area   *Area  `json:"area,omitempty" gorm:"foreignKey:AreaID"`
+	// Version se incrementa en cada actualización y se expone como ETag
+	Version uint  `json:"version" gorm:"not null;default:1"`
+	Area    *Area `json:"area,omitempty" gorm:"foreignKey:AreaID"`
 }
 
 // TableName especifica el nombre de la tabla en la base de datos
 func (Persona) TableName() string {
 	return "personas"
 }
+
+// NormalizeEmail quita los espacios del email y lo pasa a minúsculas. Los emails se guardan y
+// se buscan normalizados, de modo que Juan@X.com y juan@x.com son el mismo email.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// AuditFields retorna los campos editables que se registran en la auditoría
+func (p *Persona) AuditFields() map[string]interface{} {
+	return map[string]interface{}{
+		"nombre":  p.Nombre,
+		"email":   p.Email,
+		"area_id": p.AreaID,
+	}
+}
@@ -0,0 +1,94 @@
+package model
+
+const (
+	// DefaultPageSize es el tamaño de página usado cuando el cliente no lo indica
+	DefaultPageSize = 20
+	// MaxPageSize es el tamaño de página máximo permitido
+	MaxPageSize = 100
+)
+
+// SortField representa un campo de ordenamiento y su dirección
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// DeletedScope indica si un listado considera los registros eliminados (soft delete)
+type DeletedScope string
+
+const (
+	// DeletedExclude lista solo los registros vigentes
+	DeletedExclude DeletedScope = ""
+	// DeletedInclude lista los registros vigentes y los eliminados
+	DeletedInclude DeletedScope = "include"
+	// DeletedOnly lista solo los registros eliminados
+	DeletedOnly DeletedScope = "only"
+)
+
+// ListOptions contiene los parámetros comunes de paginación y ordenamiento
+type ListOptions struct {
+	Page     int
+	PageSize int
+	Sort     []SortField
+	Deleted  DeletedScope
+}
+
+// Normalize aplica los valores por defecto y los límites de paginación
+func (o *ListOptions) Normalize() {
+	if o.Page < 1 {
+		o.Page = 1
+	}
+	if o.PageSize < 1 {
+		o.PageSize = DefaultPageSize
+	}
+	if o.PageSize > MaxPageSize {
+		o.PageSize = MaxPageSize
+	}
+}
+
+// Offset retorna la cantidad de registros a omitir para la página actual
+func (o ListOptions) Offset() int {
+	return (o.Page - 1) * o.PageSize
+}
+
+// PersonaQuery contiene los filtros, la paginación y el orden del listado de personas
+type PersonaQuery struct {
+	ListOptions
+	AreaID      uint
+	Nombre      string
+	EmailDomain string
+}
+
+// PersonaSortFields son los campos por los que se puede ordenar el listado de personas
+var PersonaSortFields = map[string]bool{
+	"id":         true,
+	"nombre":     true,
+	"email":      true,
+	"area_id":    true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// AreaQuery contiene la búsqueda, la paginación y el orden del listado de áreas
+type AreaQuery struct {
+	ListOptions
+	Q             string
+	IncludeConteo bool
+}
+
+// AreaSortFields son los campos por los que se puede ordenar el listado de áreas
+var AreaSortFields = map[string]bool{
+	"id":         true,
+	"nombre":     true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// AreaConteoSortFields agrega el conteo de personas a los campos ordenables cuando se usa include=conteo
+var AreaConteoSortFields = map[string]bool{
+	"id":         true,
+	"nombre":     true,
+	"created_at": true,
+	"updated_at": true,
+	"personas":   true,
+}
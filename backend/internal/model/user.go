@@ -0,0 +1,102 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Role es el rol de un usuario y determina qué operaciones puede realizar
+type Role string
+
+const (
+	RoleAdmin       Role = "admin"
+	RoleHREditor    Role = "hr-editor"
+	RoleAreaManager Role = "area-manager"
+	RoleViewer      Role = "viewer"
+)
+
+// Valid indica si el rol es uno de los roles conocidos
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleHREditor, RoleAreaManager, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// User es un usuario que puede autenticarse en la API
+type User struct {
+	gorm.Model
+	Username     string `json:"username" gorm:"type:varchar(100);not null;uniqueIndex:idx_users_username_live,where:deleted_at IS NULL"`
+	PasswordHash string `json:"-" gorm:"type:varchar(100);not null"`
+	Role         Role   `json:"role" gorm:"type:varchar(20);not null;default:viewer"`
+	// AreaID es el área que gestiona un usuario con rol area-manager
+	AreaID *uint `json:"area_id,omitempty"`
+	Area   *Area `json:"-" gorm:"foreignKey:AreaID"`
+}
+
+// TableName especifica el nombre de la tabla en la base de datos
+func (User) TableName() string {
+	return "users"
+}
+
+// UserRequest son los datos para crear un usuario. AreaID es obligatorio para el rol
+// area-manager y no se admite para los demás roles.
+type UserRequest struct {
+	Username string `json:"username" binding:"required,max=100"`
+	Password string `json:"password" binding:"required"`
+	Role     Role   `json:"role" binding:"required"`
+	AreaID   *uint  `json:"area_id"`
+}
+
+// RefreshToken es un token de renovación emitido a un usuario. Solo se guarda el hash del token;
+// al usarlo se revoca y se emite uno nuevo de la misma familia.
+type RefreshToken struct {
+	ID        uint      `gorm:"primarykey"`
+	UserID    uint      `gorm:"not null;index"`
+	Family    string    `gorm:"type:varchar(64);not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	RevokedAt *time.Time
+	CreatedAt time.Time
+	User      *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName especifica el nombre de la tabla en la base de datos
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// TokenPair es el par de tokens entregado al iniciar sesión o al renovarla
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// Principal identifica a quien realiza una petición: un usuario con su rol o una API key con sus scopes
+type Principal struct {
+	UserID   uint      `json:"user_id,omitempty"`
+	Username string    `json:"username"`
+	Role     Role      `json:"role,omitempty"`
+	AreaID   uint      `json:"area_id,omitempty"`
+	APIKeyID uint      `json:"api_key_id,omitempty"`
+	Scopes   ScopeList `json:"scopes,omitempty"`
+}
+
+// principalContextKey es la clave del contexto donde viaja quien realiza la petición
+type principalContextKey struct{}
+
+// ContextWithPrincipal retorna una copia de ctx que lleva a quien realiza la petición
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext obtiene a quien realiza la petición, o nil si el contexto no lo lleva
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return principal
+}
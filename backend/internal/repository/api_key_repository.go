@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	GetAll(ctx context.Context) ([]model.APIKey, error)
+	GetByID(ctx context.Context, id uint) (*model.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (*model.APIKey, error)
+	Revoke(ctx context.Context, id uint) error
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// GetAll obtiene todas las API keys, de la más reciente a la más antigua
+func (r *apiKeyRepository) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	err := r.db.WithContext(ctx).Order("created_at DESC, id DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uint) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.WithContext(ctx).First(&key, id).Error
+	return &key, err
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", hash).First(&key).Error
+	return &key, err
+}
+
+// Revoke revoca una API key; si no existe o ya estaba revocada retorna gorm.ErrRecordNotFound
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed registra el último uso de la API key
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.APIKey{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
@@ -2,16 +2,22 @@ package repository
 
 import (
 	"backend/internal/model"
+	"context"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AreaRepository interface {
-	Create(area *model.Area) error
-	GetAll() ([]model.Area, error)
-	GetByID(id uint) (*model.Area, error)
-	Update(area *model.Area) error
-	Delete(id uint) error
-	GetAreasConConteo() ([]model.AreaConConteo, error)
+	Create(ctx context.Context, area *model.Area, entry *model.AuditEntry) error
+	GetAll(ctx context.Context, query model.AreaQuery) ([]model.Area, int64, error)
+	GetByID(ctx context.Context, id uint) (*model.Area, error)
+	GetByIDWithDeleted(ctx context.Context, id uint) (*model.Area, error)
+	GetByNombre(ctx context.Context, nombre string) (*model.Area, error)
+	GetExistingIDs(ctx context.Context, ids []uint) ([]uint, error)
+	Update(ctx context.Context, area *model.Area, entry *model.AuditEntry) error
+	Delete(ctx context.Context, id, version uint, opts model.AreaDeleteOptions, entry *model.AuditEntry) (int64, error)
+	Restore(ctx context.Context, id uint, entry *model.AuditEntry) error
+	GetAreasConConteo(ctx context.Context) ([]model.AreaConConteo, error)
 }
 
 type areaRepository struct {
@@ -22,33 +28,197 @@ func NewAreaRepository(db *gorm.DB) AreaRepository {
 	return &areaRepository{db: db}
 }
 
-func (r *areaRepository) Create(area *model.Area) error {
-	return r.db.Create(area).Error
+// Create crea el área y registra la entrada de auditoría en la misma transacción
+func (r *areaRepository) Create(ctx context.Context, area *model.Area, entry *model.AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(area).Error; err != nil {
+			return err
+		}
+		if entry != nil {
+			entry.EntityID = area.ID
+		}
+		return recordAudit(tx, entry)
+	})
 }
 
-func (r *areaRepository) GetAll() ([]model.Area, error) {
+func (r *areaRepository) GetAll(ctx context.Context, query model.AreaQuery) ([]model.Area, int64, error) {
+	var total int64
+	if err := r.filter(ctx, query).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	db := r.filter(ctx, query)
+	if query.IncludeConteo {
+		db = db.Select("areas.*, COUNT(personas.id) as personas").
+			Joins("LEFT JOIN personas ON personas.area_id = areas.id AND personas.deleted_at IS NULL").
+			Group("areas.id")
+	}
+
 	var areas []model.Area
-	err := r.db.Find(&areas).Error
-	return areas, err
+	db = applySort(db, "areas", query.Sort, "personas")
+	err := paginate(db, query.ListOptions).Find(&areas).Error
+	return areas, total, err
+}
+
+// filter construye la consulta base con la búsqueda del listado
+func (r *areaRepository) filter(ctx context.Context, query model.AreaQuery) *gorm.DB {
+	db := applyDeletedScope(r.db.WithContext(ctx).Model(&model.Area{}), "areas", query.Deleted)
+	if query.Q != "" {
+		like := "%" + likeEscaper.Replace(query.Q) + "%"
+		db = db.Where("areas.nombre ILIKE ? OR areas.descripcion ILIKE ?", like, like)
+	}
+	return db
 }
 
-func (r *areaRepository) GetByID(id uint) (*model.Area, error) {
+func (r *areaRepository) GetByID(ctx context.Context, id uint) (*model.Area, error) {
 	var area model.Area
-	err := r.db.First(&area, id).Error
+	err := r.db.WithContext(ctx).First(&area, id).Error
 	return &area, err
 }
 
-func (r *areaRepository) Update(area *model.Area) error {
-	return r.db.Save(area).Error
+// GetByIDWithDeleted obtiene un área por ID aunque esté eliminada
+func (r *areaRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*model.Area, error) {
+	var area model.Area
+	err := r.db.WithContext(ctx).Unscoped().First(&area, id).Error
+	return &area, err
+}
+
+// GetByNombre obtiene un área vigente por su nombre, sin distinguir mayúsculas
+func (r *areaRepository) GetByNombre(ctx context.Context, nombre string) (*model.Area, error) {
+	var area model.Area
+	err := r.db.WithContext(ctx).Where("LOWER(nombre) = LOWER(?)", nombre).First(&area).Error
+	return &area, err
+}
+
+// GetExistingIDs retorna cuáles de los IDs pertenecen a áreas vigentes
+func (r *areaRepository) GetExistingIDs(ctx context.Context, ids []uint) ([]uint, error) {
+	var existing []uint
+	err := r.db.WithContext(ctx).Model(&model.Area{}).Where("id IN ?", ids).Pluck("id", &existing).Error
+	return existing, err
+}
+
+// Update guarda el área si su versión no cambió desde que fue leída e incrementa la versión
+func (r *areaRepository) Update(ctx context.Context, area *model.Area, entry *model.AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, area, &area.Version); err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
+}
+
+// Delete elimina el área y, según las opciones, reasigna o elimina sus personas en la misma transacción.
+// Si version no es cero, el área solo se elimina si conserva esa versión. Retorna la cantidad de personas afectadas.
+// Sin reasignar ni eliminar en cascada, retorna *AreaHasPersonasError si el área tiene personas.
+// Además de entry, se registra una entrada de auditoría por cada persona reasignada o eliminada.
+func (r *areaRepository) Delete(ctx context.Context, id, version uint, opts model.AreaDeleteOptions, entry *model.AuditEntry) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if opts.ReassignTo != 0 {
+			if err := lockAreas(tx, opts.ReassignTo); err != nil {
+				return err
+			}
+		}
+		if opts.Hard {
+			// Session evita que las consultas siguientes acumulen condiciones sobre la misma instancia
+			tx = tx.Unscoped().Session(&gorm.Session{})
+		}
+
+		// El área queda bloqueada hasta el fin de la transacción: una persona que se le asigna
+		// mientras tanto espera (ver lockAreas), por lo que el conteo y la cascada la incluyen
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&model.Area{}, id).Error; err != nil {
+			return err
+		}
+		if opts.ReassignTo == 0 && !opts.Cascade {
+			var count int64
+			if err := tx.Model(&model.Persona{}).Where("area_id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return &AreaHasPersonasError{Count: count}
+			}
+		}
+
+		entries, err := personaAuditEntries(tx, id, opts, entry)
+		if err != nil {
+			return err
+		}
+
+		var result *gorm.DB
+		switch {
+		case opts.ReassignTo != 0:
+			result = tx.Model(&model.Persona{}).Where("area_id = ?", id).Updates(map[string]interface{}{
+				"area_id": opts.ReassignTo,
+				"version": gorm.Expr("version + 1"),
+			})
+		case opts.Cascade:
+			result = tx.Where("area_id = ?", id).Delete(&model.Persona{})
+		}
+		if result != nil {
+			if result.Error != nil {
+				return result.Error
+			}
+			affected = result.RowsAffected
+		}
+
+		if err := deleteVersioned(tx, &model.Area{}, id, version); err != nil {
+			return err
+		}
+		return recordAudit(tx, append(entries, entry)...)
+	})
+	return affected, err
+}
+
+// personaAuditEntries arma las entradas de auditoría de las personas del área que la eliminación
+// reasigna o elimina, con el mismo autor que la entrada del área
+func personaAuditEntries(tx *gorm.DB, areaID uint, opts model.AreaDeleteOptions, entry *model.AuditEntry) ([]*model.AuditEntry, error) {
+	if entry == nil || (opts.ReassignTo == 0 && !opts.Cascade) {
+		return nil, nil
+	}
+
+	var personas []model.Persona
+	if err := tx.Where("area_id = ?", areaID).Find(&personas).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]*model.AuditEntry, len(personas))
+	for i := range personas {
+		persona := &personas[i]
+		personaEntry := *entry
+		personaEntry.Entity = model.AuditEntityPersona
+		personaEntry.EntityID = persona.ID
+
+		switch {
+		case opts.ReassignTo != 0:
+			moved := *persona
+			moved.AreaID = opts.ReassignTo
+			personaEntry.Action = model.AuditUpdate
+			personaEntry.Changes = model.NewAuditChanges(persona.AuditFields(), moved.AuditFields())
+		case opts.Hard:
+			personaEntry.Action = model.AuditPurge
+			personaEntry.Changes = model.NewAuditChanges(persona.AuditFields(), nil)
+		default:
+			personaEntry.Action = model.AuditDelete
+			personaEntry.Changes = model.NewAuditChanges(persona.AuditFields(), nil)
+		}
+		entries[i] = &personaEntry
+	}
+	return entries, nil
 }
 
-func (r *areaRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Area{}, id).Error
+// Restore revierte la eliminación de un área
+func (r *areaRepository) Restore(ctx context.Context, id uint, entry *model.AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := restoreDeleted(tx, &model.Area{}, id); err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
 }
 
-func (r *areaRepository) GetAreasConConteo() ([]model.AreaConConteo, error) {
+func (r *areaRepository) GetAreasConConteo(ctx context.Context) ([]model.AreaConConteo, error) {
 	var results []model.AreaConConteo
-	err := r.db.Model(&model.Area{}).
+	err := r.db.WithContext(ctx).Model(&model.Area{}).
 		Select("areas.id, areas.nombre, areas.descripcion, COUNT(personas.id) as personas").
 		Joins("LEFT JOIN personas ON personas.area_id = areas.id AND personas.deleted_at IS NULL").
 		Group("areas.id, areas.nombre, areas.descripcion").
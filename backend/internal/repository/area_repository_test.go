@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"backend/internal/testdb"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// setupAreaDelete crea las áreas Ventas, con dos personas, y Marketing
+func setupAreaDelete(t *testing.T, db *gorm.DB) (ventas, marketing *model.Area) {
+	t.Helper()
+	ctx := context.Background()
+	repos := newRepositories(db)
+
+	ventas = &model.Area{Nombre: "Ventas"}
+	marketing = &model.Area{Nombre: "Marketing"}
+	for _, area := range []*model.Area{ventas, marketing} {
+		if err := repos.Areas.Create(ctx, area, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, email := range []string{"ana@test.com", "juan@test.com"} {
+		if err := repos.Personas.Create(ctx, &model.Persona{Nombre: "Persona", Email: email, AreaID: ventas.ID}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ventas, marketing
+}
+
+// TestAreaDeleteWithPersonas prueba que la eliminación cuente, reasigne o elimine las personas
+// del área dentro de su transacción
+func TestAreaDeleteWithPersonas(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     func(marketing *model.Area) model.AreaDeleteOptions
+		expected int64
+		// remaining es la cantidad de personas que deben quedar en la tabla, incluidas las eliminadas
+		remaining int64
+	}{
+		{
+			name: "Reasignar",
+			opts: func(marketing *model.Area) model.AreaDeleteOptions {
+				return model.AreaDeleteOptions{ReassignTo: marketing.ID}
+			},
+			expected:  2,
+			remaining: 2,
+		},
+		{
+			name:      "Cascada",
+			opts:      func(*model.Area) model.AreaDeleteOptions { return model.AreaDeleteOptions{Cascade: true} },
+			expected:  2,
+			remaining: 2,
+		},
+		{
+			name:      "Cascada definitiva",
+			opts:      func(*model.Area) model.AreaDeleteOptions { return model.AreaDeleteOptions{Cascade: true, Hard: true} },
+			expected:  2,
+			remaining: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			db := testdb.Open(t)
+			ventas, marketing := setupAreaDelete(t, db)
+			repo := NewAreaRepository(db)
+
+			// Act
+			affected, err := repo.Delete(context.Background(), ventas.ID, 0, tt.opts(marketing), nil)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+			}
+			if affected != tt.expected {
+				t.Errorf("Se esperaban %d personas afectadas, pero se obtuvo: %d", tt.expected, affected)
+			}
+			var remaining int64
+			if err := db.Unscoped().Model(&model.Persona{}).Count(&remaining).Error; err != nil {
+				t.Fatal(err)
+			}
+			if remaining != tt.remaining {
+				t.Errorf("Se esperaban %d personas en la tabla, pero se obtuvo: %d", tt.remaining, remaining)
+			}
+		})
+	}
+}
+
+// TestAreaDeleteRefused prueba que no se elimine un área con personas ni se reasignen a un área eliminada
+func TestAreaDeleteRefused(t *testing.T) {
+	// Arrange
+	db := testdb.Open(t)
+	ventas, marketing := setupAreaDelete(t, db)
+	repo := NewAreaRepository(db)
+	ctx := context.Background()
+	if _, err := repo.Delete(ctx, marketing.ID, 0, model.AreaDeleteOptions{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, refusedErr := repo.Delete(ctx, ventas.ID, 0, model.AreaDeleteOptions{}, nil)
+	_, reassignErr := repo.Delete(ctx, ventas.ID, 0, model.AreaDeleteOptions{ReassignTo: marketing.ID}, nil)
+	createErr := NewPersonaRepository(db).Create(ctx, &model.Persona{Nombre: "Ana", Email: "otra@test.com", AreaID: marketing.ID}, nil)
+
+	// Assert
+	var hasPersonas *AreaHasPersonasError
+	if !errors.As(refusedErr, &hasPersonas) || hasPersonas.Count != 2 {
+		t.Errorf("Se esperaba AreaHasPersonasError con 2 personas, pero se obtuvo: %v", refusedErr)
+	}
+	if !errors.Is(reassignErr, ErrAreaUnavailable) {
+		t.Errorf("Se esperaba ErrAreaUnavailable al reasignar, pero se obtuvo: %v", reassignErr)
+	}
+	if !errors.Is(createErr, ErrAreaUnavailable) {
+		t.Errorf("Se esperaba ErrAreaUnavailable al crear, pero se obtuvo: %v", createErr)
+	}
+	if _, err := repo.GetByID(ctx, ventas.ID); err != nil {
+		t.Errorf("Se esperaba que el área siguiera vigente, pero se obtuvo: %v", err)
+	}
+}
+
+// TestAreaDeleteWaitsForPersonaCreate prueba que la eliminación espere a una persona que se
+// está asignando al área y la cuente
+func TestAreaDeleteWaitsForPersonaCreate(t *testing.T) {
+	// Arrange
+	db := testdb.Open(t)
+	area := &model.Area{Nombre: "Ventas"}
+	if err := NewAreaRepository(db).Create(context.Background(), area, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := db.Begin()
+	defer tx.Rollback()
+	if err := lockAreas(tx, area.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Create(&model.Persona{Nombre: "Ana", Email: "ana@test.com", AreaID: area.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	done := make(chan error, 1)
+	go func() {
+		_, err := NewAreaRepository(db).Delete(context.Background(), area.ID, 0, model.AreaDeleteOptions{}, nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Se esperaba que la eliminación esperara a la persona, pero terminó con: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if err := tx.Commit().Error; err != nil {
+		t.Fatal(err)
+	}
+	err := <-done
+
+	// Assert
+	var hasPersonas *AreaHasPersonasError
+	if !errors.As(err, &hasPersonas) || hasPersonas.Count != 1 {
+		t.Errorf("Se esperaba AreaHasPersonasError con 1 persona, pero se obtuvo: %v", err)
+	}
+}
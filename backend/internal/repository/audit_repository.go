@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	GetAll(ctx context.Context, query model.AuditQuery) ([]model.AuditEntry, int64, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// GetAll obtiene las entradas de auditoría que cumplen los filtros, de la más reciente a la más antigua
+func (r *auditRepository) GetAll(ctx context.Context, query model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	db := r.db.WithContext(ctx).Model(&model.AuditEntry{})
+	if query.Entity != "" {
+		db = db.Where("entity = ?", query.Entity)
+	}
+	if query.EntityID != 0 {
+		db = db.Where("entity_id = ?", query.EntityID)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.Actor != "" {
+		db = db.Where("actor = ?", query.Actor)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []model.AuditEntry
+	err := paginate(db.Order("created_at DESC, id DESC"), query.ListOptions).Find(&entries).Error
+	return entries, total, err
+}
+
+// recordAudit guarda las entradas de auditoría de una operación en la transacción tx,
+// de modo que se confirman o se descartan junto con el cambio. Las entradas nil se omiten.
+func recordAudit(tx *gorm.DB, entries ...*model.AuditEntry) error {
+	batch := make([]*model.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry != nil {
+			batch = append(batch, entry)
+		}
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(batch, importBatchSize).Error
+}
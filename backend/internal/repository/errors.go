@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVersionConflict indica que el registro cambió de versión desde que fue leído
+var ErrVersionConflict = errors.New("el registro fue modificado por otra operación")
+
+// ErrAreaUnavailable indica que el área a la que se asigna una persona no existe o está eliminada
+var ErrAreaUnavailable = errors.New("el área no existe o está eliminada")
+
+// AreaHasPersonasError indica que el área no se eliminó porque todavía tiene personas asignadas
+type AreaHasPersonasError struct {
+	Count int64
+}
+
+func (e *AreaHasPersonasError) Error() string {
+	return fmt.Sprintf("el área tiene %d personas asignadas", e.Count)
+}
+
+// saveVersioned guarda value solo si su versión sigue siendo la que se leyó e incrementa la versión.
+// Nunca inserta: si no hay fila con esa versión retorna ErrVersionConflict.
+func saveVersioned(db *gorm.DB, value interface{}, version *uint, omit ...string) error {
+	expected := *version
+	*version = expected + 1
+
+	omit = append(omit, "id", "created_at", "deleted_at")
+	result := db.Select("*").Omit(omit...).Where("version = ?", expected).Save(value)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		*version = expected
+	}
+	return result.Error
+}
+
+// deleteVersioned elimina (soft delete) el registro id; si version no es cero, solo si coincide
+func deleteVersioned(db *gorm.DB, value interface{}, id, version uint) error {
+	if version != 0 {
+		db = db.Where("version = ?", version)
+	}
+
+	result := db.Delete(value, id)
+	if result.Error == nil && result.RowsAffected == 0 {
+		if version != 0 {
+			return ErrVersionConflict
+		}
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+// restoreDeleted revierte el soft delete del registro id e incrementa su versión
+func restoreDeleted(db *gorm.DB, value interface{}, id uint) error {
+	result := db.Unscoped().Model(value).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+// lockAreas bloquea con FOR SHARE las áreas vigentes ids hasta el fin de la transacción, para
+// que no se eliminen mientras se les asignan personas. Si alguna no existe o está eliminada
+// retorna ErrAreaUnavailable.
+func lockAreas(tx *gorm.DB, ids ...uint) error {
+	unique := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+
+	var found []uint
+	err := tx.Model(&model.Area{}).Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("id IN ?", ids).Pluck("id", &found).Error
+	if err != nil {
+		return err
+	}
+	if len(found) != len(unique) {
+		return ErrAreaUnavailable
+	}
+	return nil
+}
@@ -2,16 +2,25 @@ package repository
 
 import (
 	"backend/internal/model"
+	"context"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PersonaRepository interface {
-	Create(persona *model.Persona) error
-	GetAll() ([]model.Persona, error)
-	GetByID(id uint) (*model.Persona, error)
-	GetByEmail(email string) (*model.Persona, error)
-	Update(persona *model.Persona) error
-	Delete(id uint) error
+	Create(ctx context.Context, persona *model.Persona, entry *model.AuditEntry) error
+	CreateBatch(ctx context.Context, personas []model.Persona, entries []model.AuditEntry) error
+	GetAll(ctx context.Context, query model.PersonaQuery) ([]model.Persona, int64, error)
+	Export(ctx context.Context, query model.PersonaQuery, fn func(row *model.PersonaExport) error) error
+	GetByID(ctx context.Context, id uint) (*model.Persona, error)
+	GetByIDWithDeleted(ctx context.Context, id uint) (*model.Persona, error)
+	GetByEmail(ctx context.Context, email string) (*model.Persona, error)
+	GetExistingEmails(ctx context.Context, emails []string) ([]string, error)
+	GetByArea(ctx context.Context, areaID uint) ([]model.Persona, error)
+	Update(ctx context.Context, persona *model.Persona, entry *model.AuditEntry) error
+	Delete(ctx context.Context, id, version uint, entry *model.AuditEntry) error
+	Purge(ctx context.Context, id, version uint, entry *model.AuditEntry) error
+	Restore(ctx context.Context, id uint, entry *model.AuditEntry) error
 }
 
 type personaRepository struct {
@@ -22,32 +31,178 @@ func NewPersonaRepository(db *gorm.DB) PersonaRepository {
 	return &personaRepository{db: db}
 }
 
-func (r *personaRepository) Create(persona *model.Persona) error {
-	return r.db.Create(persona).Error
+// Create crea la persona y registra la entrada de auditoría en la misma transacción. Si el área
+// no existe o está eliminada retorna ErrAreaUnavailable.
+func (r *personaRepository) Create(ctx context.Context, persona *model.Persona, entry *model.AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAreas(tx, persona.AreaID); err != nil {
+			return err
+		}
+		if err := tx.Create(persona).Error; err != nil {
+			return err
+		}
+		if entry != nil {
+			entry.EntityID = persona.ID
+		}
+		return recordAudit(tx, entry)
+	})
 }
 
-func (r *personaRepository) GetAll() ([]model.Persona, error) {
+// importBatchSize es la cantidad de filas por INSERT al crear personas en lote
+const importBatchSize = 500
+
+// CreateBatch crea todas las personas en una sola transacción; si una falla no se crea ninguna.
+// entries[i] es la entrada de auditoría de personas[i].
+func (r *personaRepository) CreateBatch(ctx context.Context, personas []model.Persona, entries []model.AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		areaIDs := make([]uint, len(personas))
+		for i := range personas {
+			areaIDs[i] = personas[i].AreaID
+		}
+		if err := lockAreas(tx, areaIDs...); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).CreateInBatches(personas, importBatchSize).Error; err != nil {
+			return err
+		}
+
+		batch := make([]*model.AuditEntry, len(entries))
+		for i := range entries {
+			entries[i].EntityID = personas[i].ID
+			batch[i] = &entries[i]
+		}
+		return recordAudit(tx, batch...)
+	})
+}
+
+func (r *personaRepository) GetAll(ctx context.Context, query model.PersonaQuery) ([]model.Persona, int64, error) {
+	var total int64
+	if err := r.filter(ctx, query).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	var personas []model.Persona
-	err := r.db.Preload("Area").Find(&personas).Error
-	return personas, err
+	db := applySort(r.filter(ctx, query).Preload("Area"), "personas", query.Sort)
+	err := paginate(db, query.ListOptions).Find(&personas).Error
+	return personas, total, err
+}
+
+// Export recorre con un cursor todas las personas que cumplen los filtros, sin paginar,
+// y llama a fn con cada fila; si fn retorna un error el recorrido se detiene
+func (r *personaRepository) Export(ctx context.Context, query model.PersonaQuery, fn func(row *model.PersonaExport) error) error {
+	db := r.filter(ctx, query).
+		Select("personas.id, personas.nombre, personas.email, personas.area_id, areas.nombre AS area_nombre, personas.created_at, personas.updated_at").
+		Joins("LEFT JOIN areas ON areas.id = personas.area_id")
+
+	rows, err := applySort(db, "personas", query.Sort).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.PersonaExport
+		if err := r.db.WithContext(ctx).ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filter construye la consulta base con los filtros del listado
+func (r *personaRepository) filter(ctx context.Context, query model.PersonaQuery) *gorm.DB {
+	db := applyDeletedScope(r.db.WithContext(ctx).Model(&model.Persona{}), "personas", query.Deleted)
+	if query.AreaID != 0 {
+		db = db.Where("personas.area_id = ?", query.AreaID)
+	}
+	if query.Nombre != "" {
+		db = db.Where("personas.nombre ILIKE ?", "%"+likeEscaper.Replace(query.Nombre)+"%")
+	}
+	if query.EmailDomain != "" {
+		db = db.Where("personas.email ILIKE ?", "%@"+likeEscaper.Replace(query.EmailDomain))
+	}
+	return db
+}
+
+func (r *personaRepository) GetByID(ctx context.Context, id uint) (*model.Persona, error) {
+	var persona model.Persona
+	err := r.db.WithContext(ctx).Preload("Area").First(&persona, id).Error
+	return &persona, err
 }
 
-func (r *personaRepository) GetByID(id uint) (*model.Persona, error) {
+// GetByIDWithDeleted obtiene una persona por ID aunque esté eliminada
+func (r *personaRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*model.Persona, error) {
 	var persona model.Persona
-	err := r.db.Preload("Area").First(&persona, id).Error
+	err := r.db.WithContext(ctx).Unscoped().First(&persona, id).Error
 	return &persona, err
 }
 
-func (r *personaRepository) GetByEmail(email string) (*model.Persona, error) {
+// GetByEmail obtiene una persona vigente por email, sin distinguir mayúsculas. La comparación
+// sobre LOWER(email) usa el índice único idx_personas_email_live.
+func (r *personaRepository) GetByEmail(ctx context.Context, email string) (*model.Persona, error) {
 	var persona model.Persona
-	err := r.db.Where("email = ?", email).First(&persona).Error
+	err := r.db.WithContext(ctx).Where("LOWER(email) = ?", model.NormalizeEmail(email)).First(&persona).Error
 	return &persona, err
 }
 
-func (r *personaRepository) Update(persona *model.Persona) error {
-	return r.db.Save(persona).Error
+// GetExistingEmails retorna, en minúsculas, cuáles de los emails pertenecen a personas vigentes.
+// Los emails deben venir normalizados; la consulta usa el índice idx_personas_email_live.
+func (r *personaRepository) GetExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	var existing []string
+	err := r.db.WithContext(ctx).Model(&model.Persona{}).Where("LOWER(email) IN ?", emails).Pluck("LOWER(email)", &existing).Error
+	return existing, err
+}
+
+// GetByArea obtiene las personas vigentes del área, ordenadas por id
+func (r *personaRepository) GetByArea(ctx context.Context, areaID uint) ([]model.Persona, error) {
+	var personas []model.Persona
+	err := r.db.WithContext(ctx).Where("area_id = ?", areaID).Order("id").Find(&personas).Error
+	return personas, err
+}
+
+// Update guarda la persona si su versión no cambió desde que fue leída e incrementa la versión.
+// El área precargada no se guarda; solo cuenta area_id.
+func (r *personaRepository) Update(ctx context.Context, persona *model.Persona, entry *model.AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAreas(tx, persona.AreaID); err != nil {
+			return err
+		}
+		if err := saveVersioned(tx, persona, &persona.Version, clause.Associations); err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
+}
+
+// Delete elimina la persona; si version no es cero, solo si conserva esa versión
+func (r *personaRepository) Delete(ctx context.Context, id, version uint, entry *model.AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteVersioned(tx, &model.Persona{}, id, version); err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
+}
+
+// Purge elimina definitivamente la persona, esté vigente o eliminada; si version no es cero, solo si conserva esa versión
+func (r *personaRepository) Purge(ctx context.Context, id, version uint, entry *model.AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteVersioned(tx.Unscoped(), &model.Persona{}, id, version); err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
 }
 
-func (r *personaRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Persona{}, id).Error
+// Restore revierte la eliminación de una persona
+func (r *personaRepository) Restore(ctx context.Context, id uint, entry *model.AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := restoreDeleted(tx, &model.Persona{}, id); err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
 }
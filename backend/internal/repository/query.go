@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeEscaper escapa los comodines de LIKE para que el texto se busque literalmente
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// applySort agrega el ORDER BY solicitado y desempata siempre por id para un orden estable.
+// Los campos en computed son alias del SELECT y no se califican con el nombre de la tabla.
+func applySort(db *gorm.DB, table string, sort []model.SortField, computed ...string) *gorm.DB {
+	hasID := false
+	for _, s := range sort {
+		column := clause.Column{Table: table, Name: s.Field}
+		if slices.Contains(computed, s.Field) {
+			column.Table = ""
+		}
+		db = db.Order(clause.OrderByColumn{Column: column, Desc: s.Desc})
+		if s.Field == "id" {
+			hasID = true
+		}
+	}
+	if !hasID {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Table: table, Name: "id"}})
+	}
+	return db
+}
+
+// paginate aplica el OFFSET y LIMIT de la página solicitada
+func paginate(db *gorm.DB, opts model.ListOptions) *gorm.DB {
+	return db.Offset(opts.Offset()).Limit(opts.PageSize)
+}
+
+// applyDeletedScope ajusta la consulta para incluir o listar solo los registros eliminados
+func applyDeletedScope(db *gorm.DB, table string, scope model.DeletedScope) *gorm.DB {
+	switch scope {
+	case model.DeletedInclude:
+		return db.Unscoped()
+	case model.DeletedOnly:
+		return db.Unscoped().Where(clause.Expr{SQL: "? IS NOT NULL", Vars: []interface{}{clause.Column{Table: table, Name: "deleted_at"}}})
+	}
+	return db
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrTokenRevoked indica que el token de renovación ya fue usado o revocado
+var ErrTokenRevoked = errors.New("el token de renovación ya fue revocado")
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *model.RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (*model.RefreshToken, error)
+	Rotate(ctx context.Context, old *model.RefreshToken, next *model.RefreshToken) error
+	Revoke(ctx context.Context, id uint) error
+	RevokeFamily(ctx context.Context, family string) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	return &token, err
+}
+
+// Rotate revoca el token usado y guarda el siguiente en la misma transacción.
+// Si otro pedido ya revocó el token, no se guarda el siguiente y se retorna ErrTokenRevoked.
+func (r *refreshTokenRepository) Rotate(ctx context.Context, old *model.RefreshToken, next *model.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := revokeToken(tx.Where("id = ?", old.ID)); err != nil {
+			return err
+		}
+		return tx.Create(next).Error
+	})
+}
+
+// Revoke revoca un token; si ya estaba revocado retorna ErrTokenRevoked
+func (r *refreshTokenRepository) Revoke(ctx context.Context, id uint) error {
+	return revokeToken(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// RevokeFamily revoca todos los tokens vigentes emitidos a partir del mismo inicio de sesión
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, family string) error {
+	return r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("family = ? AND revoked_at IS NULL", family).
+		Update("revoked_at", time.Now()).Error
+}
+
+// revokeToken marca como revocados los tokens vigentes de la consulta
+func revokeToken(db *gorm.DB) error {
+	result := db.Model(&model.RefreshToken{}).Where("revoked_at IS NULL").Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTokenRevoked
+	}
+	return nil
+}
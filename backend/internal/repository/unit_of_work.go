@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repositories reúne los repositorios que comparten una misma conexión o transacción
+type Repositories struct {
+	Areas    AreaRepository
+	Personas PersonaRepository
+	Audit    AuditRepository
+}
+
+// UnitOfWork ejecuta operaciones que abarcan varios repositorios en una sola transacción
+type UnitOfWork interface {
+	// WithTx llama a fn con repositorios ligados a una transacción nueva. La transacción se
+	// confirma si fn retorna nil y se revierte si retorna un error o entra en pánico; el
+	// pánico se propaga después de revertir. Las transacciones propias de cada método de los
+	// repositorios pasan a ser savepoints dentro de la de fn.
+	WithTx(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) WithTx(ctx context.Context, fn func(repos Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(newRepositories(tx))
+	})
+}
+
+// newRepositories crea los repositorios sobre db, que puede ser una transacción
+func newRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Areas:    NewAreaRepository(db),
+		Personas: NewPersonaRepository(db),
+		Audit:    NewAuditRepository(db),
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"backend/internal/testdb"
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+// Los tests de este paquete usan un Postgres real
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
+
+// auditEntry crea una entrada de auditoría de prueba
+func auditEntry(entity model.AuditEntity, action model.AuditAction) *model.AuditEntry {
+	return &model.AuditEntry{Entity: entity, Action: action, Actor: "test"}
+}
+
+// countRows cuenta las filas vigentes de una tabla
+func countRows(t *testing.T, uow *unitOfWork, value interface{}) int64 {
+	t.Helper()
+	var count int64
+	if err := uow.db.Model(value).Count(&count).Error; err != nil {
+		t.Fatalf("Error al contar filas: %v", err)
+	}
+	return count
+}
+
+// TestWithTxCommit prueba que los cambios de varios repositorios se confirmen juntos
+func TestWithTxCommit(t *testing.T) {
+	// Arrange
+	uow := NewUnitOfWork(testdb.Open(t)).(*unitOfWork)
+	ctx := context.Background()
+	origen := &model.Area{Nombre: "Ventas"}
+	if err := newRepositories(uow.db).Areas.Create(ctx, origen, nil); err != nil {
+		t.Fatal(err)
+	}
+	persona := &model.Persona{Nombre: "Ana", Email: "ana@test.com", AreaID: origen.ID}
+	if err := newRepositories(uow.db).Personas.Create(ctx, persona, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err := uow.WithTx(ctx, func(repos Repositories) error {
+		destino := &model.Area{Nombre: "Marketing"}
+		if err := repos.Areas.Create(ctx, destino, auditEntry(model.AuditEntityArea, model.AuditCreate)); err != nil {
+			return err
+		}
+		persona.AreaID = destino.ID
+		return repos.Personas.Update(ctx, persona, auditEntry(model.AuditEntityPersona, model.AuditUpdate))
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	saved, err := newRepositories(uow.db).Personas.GetByID(ctx, persona.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Area == nil || saved.Area.Nombre != "Marketing" || saved.Version != 2 {
+		t.Errorf("Se esperaba la persona en Marketing con versión 2, pero se obtuvo: %+v", saved)
+	}
+	if count := countRows(t, uow, &model.AuditEntry{}); count != 2 {
+		t.Errorf("Se esperaban 2 entradas de auditoría, pero se obtuvo: %d", count)
+	}
+}
+
+// TestWithTxRollback prueba que un error o un pánico descarten los cambios de todos los repositorios
+func TestWithTxRollback(t *testing.T) {
+	errAbort := errors.New("abortar")
+
+	tests := []struct {
+		name string
+		// fail se ejecuta después de crear el área y la persona
+		fail func(ctx context.Context, repos Repositories) error
+	}{
+		{
+			name: "Error de la función",
+			fail: func(ctx context.Context, repos Repositories) error { return errAbort },
+		},
+		{
+			name: "Error de un repositorio",
+			fail: func(ctx context.Context, repos Repositories) error {
+				// El email ya está en uso: la transacción propia de Create se revierte y el error se propaga
+				return repos.Personas.Create(ctx, &model.Persona{Nombre: "Otra Ana", Email: "ana@test.com", AreaID: 1}, nil)
+			},
+		},
+		{
+			name: "Pánico",
+			fail: func(ctx context.Context, repos Repositories) error { panic("falla inesperada") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			uow := NewUnitOfWork(testdb.Open(t)).(*unitOfWork)
+			ctx := context.Background()
+
+			// Act
+			var err error
+			panicked := func() (panicked bool) {
+				defer func() { panicked = recover() != nil }()
+				err = uow.WithTx(ctx, func(repos Repositories) error {
+					area := &model.Area{Nombre: "Ventas"}
+					if err := repos.Areas.Create(ctx, area, auditEntry(model.AuditEntityArea, model.AuditCreate)); err != nil {
+						return err
+					}
+					persona := &model.Persona{Nombre: "Ana", Email: "ana@test.com", AreaID: area.ID}
+					if err := repos.Personas.Create(ctx, persona, nil); err != nil {
+						return err
+					}
+					return tt.fail(ctx, repos)
+				})
+				return false
+			}()
+
+			// Assert
+			if tt.name == "Pánico" && !panicked {
+				t.Errorf("Se esperaba que el pánico se propagara")
+			}
+			if tt.name != "Pánico" && err == nil {
+				t.Errorf("Se esperaba un error, pero se obtuvo nil")
+			}
+			if tt.name == "Error de la función" && !errors.Is(err, errAbort) {
+				t.Errorf("Se esperaba el error de la función, pero se obtuvo: %v", err)
+			}
+
+			for _, value := range []interface{}{&model.Area{}, &model.Persona{}, &model.AuditEntry{}} {
+				if count := countRows(t, uow, value); count != 0 {
+					t.Errorf("Se esperaba que no quedaran filas de %T, pero se obtuvo: %d", value, count)
+				}
+			}
+		})
+	}
+}
+
+// TestWithTxContext prueba que la transacción respete la cancelación del contexto
+func TestWithTxContext(t *testing.T) {
+	// Arrange
+	uow := NewUnitOfWork(testdb.Open(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	err := uow.WithTx(ctx, func(repos Repositories) error {
+		return repos.Areas.Create(ctx, &model.Area{Nombre: "Ventas"}, nil)
+	})
+
+	// Assert
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Se esperaba context.Canceled, pero se obtuvo: %v", err)
+	}
+}
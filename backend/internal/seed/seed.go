@@ -0,0 +1,312 @@
+// Package seed carga datos iniciales de áreas y personas desde archivos YAML o JSON.
+// La carga se puede repetir: las áreas se identifican por nombre y las personas por email, sin
+// distinguir mayúsculas, de modo que un registro existente se actualiza en lugar de duplicarse.
+package seed
+
+import (
+	"backend/internal/model"
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:embed fixtures/*.yaml
+var embedded embed.FS
+
+// DefaultProfile es el perfil que se carga si no se indica otro
+const DefaultProfile = "demo"
+
+// batchSize es la cantidad de filas por INSERT al cargar las personas
+const batchSize = 500
+
+// Fixtures son los datos a cargar. Cada persona referencia su área por nombre.
+type Fixtures struct {
+	Areas    []AreaFixture    `json:"areas" yaml:"areas" validate:"dive"`
+	Personas []PersonaFixture `json:"personas" yaml:"personas" validate:"dive"`
+}
+
+// AreaFixture es un área a cargar; nombre es su clave natural
+type AreaFixture struct {
+	Nombre      string `json:"nombre" yaml:"nombre" validate:"required,max=100"`
+	Descripcion string `json:"descripcion" yaml:"descripcion"`
+}
+
+// PersonaFixture es una persona a cargar; email es su clave natural
+type PersonaFixture struct {
+	Nombre string `json:"nombre" yaml:"nombre" validate:"required,max=200"`
+	Email  string `json:"email" yaml:"email" validate:"required,email,max=200"`
+	Area   string `json:"area" yaml:"area" validate:"required"`
+}
+
+// Result resume una carga: cuántos registros se crearon o cambiaron.
+// Los registros que ya tenían los mismos datos no se cuentan.
+type Result struct {
+	Areas    int64
+	Personas int64
+}
+
+var validate = validator.New()
+
+// Profiles retorna los nombres de los perfiles incluidos en el binario
+func Profiles() []string {
+	files, _ := embedded.ReadDir("fixtures")
+	profiles := make([]string, 0, len(files))
+	for _, file := range files {
+		profiles = append(profiles, strings.TrimSuffix(file.Name(), ".yaml"))
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// LoadProfile lee los datos de un perfil incluido en el binario, como demo, test o empty
+func LoadProfile(profile string) (*Fixtures, error) {
+	data, err := embedded.ReadFile("fixtures/" + profile + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("el perfil '%s' no existe; use %s", profile, strings.Join(Profiles(), ", "))
+	}
+	return Parse(data, ".yaml")
+}
+
+// LoadFile lee los datos de un archivo .yaml, .yml o .json
+func LoadFile(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixtures, err := Parse(data, filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// Parse interpreta los datos según la extensión del archivo y los valida.
+// Se rechazan los campos desconocidos para detectar errores de tipeo.
+func Parse(data []byte, ext string) (*Fixtures, error) {
+	var fixtures Fixtures
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&fixtures); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&fixtures); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("formato '%s' no soportado; use .yaml, .yml o .json", ext)
+	}
+
+	if err := fixtures.Validate(); err != nil {
+		return nil, err
+	}
+	return &fixtures, nil
+}
+
+// Validate verifica los campos de cada registro y que no se repitan nombres de área ni emails
+func (f *Fixtures) Validate() error {
+	if err := validate.Struct(f); err != nil {
+		return err
+	}
+
+	areas := map[string]bool{}
+	for _, area := range f.Areas {
+		key := strings.ToLower(area.Nombre)
+		if areas[key] {
+			return fmt.Errorf("el área '%s' está repetida", area.Nombre)
+		}
+		areas[key] = true
+	}
+
+	emails := map[string]bool{}
+	for _, persona := range f.Personas {
+		key := model.NormalizeEmail(persona.Email)
+		if emails[key] {
+			return fmt.Errorf("el email '%s' está repetido", persona.Email)
+		}
+		emails[key] = true
+	}
+	return nil
+}
+
+// Apply carga los datos en una sola transacción: crea los registros nuevos, actualiza los que
+// cambiaron y deja igual el resto. También ajusta las secuencias de IDs a los IDs existentes,
+// por si la base se cargó antes con IDs explícitos. La carga no genera entradas de auditoría.
+func Apply(ctx context.Context, db *gorm.DB, fixtures *Fixtures) (*Result, error) {
+	result := &Result{}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := syncSequences(tx); err != nil {
+			return err
+		}
+
+		var err error
+		if result.Areas, err = upsertAreas(tx, fixtures.Areas); err != nil {
+			return err
+		}
+		if result.Personas, err = upsertPersonas(tx, fixtures.Personas); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// upsertAreas crea o actualiza las áreas por nombre entre las áreas vigentes, sin distinguir
+// mayúsculas. El índice único idx_areas_nombre_live distingue mayúsculas, así que un área que ya
+// existe se carga con el nombre guardado para que ON CONFLICT la encuentre.
+func upsertAreas(tx *gorm.DB, fixtures []AreaFixture) (int64, error) {
+	if len(fixtures) == 0 {
+		return 0, nil
+	}
+
+	names := make([]string, len(fixtures))
+	for i, fixture := range fixtures {
+		names[i] = fixture.Nombre
+	}
+	existing, err := findAreas(tx, names)
+	if err != nil {
+		return 0, err
+	}
+
+	areas := make([]model.Area, len(fixtures))
+	for i, fixture := range fixtures {
+		nombre := fixture.Nombre
+		if area, ok := existing[strings.ToLower(nombre)]; ok {
+			nombre = area.Nombre
+		}
+		areas[i] = model.Area{Nombre: nombre, Descripcion: fixture.Descripcion}
+	}
+
+	res := tx.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "nombre"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"descripcion": gorm.Expr("EXCLUDED.descripcion"),
+			"updated_at":  time.Now(),
+			"version":     gorm.Expr("areas.version + 1"),
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "areas.descripcion IS DISTINCT FROM EXCLUDED.descripcion"}}},
+	}).Create(&areas)
+	return res.RowsAffected, res.Error
+}
+
+// upsertPersonas crea o actualiza las personas por email entre las personas vigentes
+func upsertPersonas(tx *gorm.DB, fixtures []PersonaFixture) (int64, error) {
+	if len(fixtures) == 0 {
+		return 0, nil
+	}
+
+	areaIDs, err := resolveAreas(tx, fixtures)
+	if err != nil {
+		return 0, err
+	}
+
+	personas := make([]model.Persona, len(fixtures))
+	for i, fixture := range fixtures {
+		personas[i] = model.Persona{
+			Nombre: fixture.Nombre,
+			Email:  model.NormalizeEmail(fixture.Email),
+			AreaID: areaIDs[strings.ToLower(fixture.Area)],
+		}
+	}
+
+	res := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+		// Debe coincidir con el índice único idx_personas_email_live sobre LOWER(email)
+		Columns:     []clause.Column{{Name: "LOWER(email)", Raw: true}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"nombre":     gorm.Expr("EXCLUDED.nombre"),
+			"area_id":    gorm.Expr("EXCLUDED.area_id"),
+			"updated_at": time.Now(),
+			"version":    gorm.Expr("personas.version + 1"),
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{
+			SQL: "(personas.nombre, personas.area_id) IS DISTINCT FROM (EXCLUDED.nombre, EXCLUDED.area_id)",
+		}}},
+	}).CreateInBatches(&personas, batchSize)
+	return res.RowsAffected, res.Error
+}
+
+// resolveAreas obtiene el ID de cada área referenciada por las personas, sin distinguir mayúsculas.
+// Un área puede venir en los mismos datos o existir de antes en la base.
+func resolveAreas(tx *gorm.DB, fixtures []PersonaFixture) (map[string]uint, error) {
+	names := make([]string, len(fixtures))
+	for i, fixture := range fixtures {
+		names[i] = fixture.Area
+	}
+
+	areas, err := findAreas(tx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]uint, len(areas))
+	for key, area := range areas {
+		ids[key] = area.ID
+	}
+	for _, fixture := range fixtures {
+		if _, ok := ids[strings.ToLower(fixture.Area)]; !ok {
+			return nil, fmt.Errorf("el área '%s' de %s no existe", fixture.Area, fixture.Email)
+		}
+	}
+	return ids, nil
+}
+
+// findAreas busca las áreas vigentes con los nombres indicados, sin distinguir mayúsculas, y las
+// retorna por nombre en minúsculas. Si dos áreas vigentes solo difieren en mayúsculas no se
+// puede saber a cuál se refieren los datos y la carga falla.
+func findAreas(tx *gorm.DB, names []string) (map[string]model.Area, error) {
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = strings.ToLower(name)
+	}
+
+	var areas []model.Area
+	if err := tx.Select("id, nombre").Where("LOWER(nombre) IN ?", keys).Find(&areas).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]model.Area, len(areas))
+	for _, area := range areas {
+		key := strings.ToLower(area.Nombre)
+		if other, ok := found[key]; ok {
+			return nil, fmt.Errorf("las áreas '%s' y '%s' solo difieren en mayúsculas; renombre una antes de cargar los datos", other.Nombre, area.Nombre)
+		}
+		found[key] = area
+	}
+	return found, nil
+}
+
+// syncSequences ajusta las secuencias de IDs para que el próximo ID no choque con uno existente
+func syncSequences(tx *gorm.DB) error {
+	for _, table := range []string{"areas", "personas"} {
+		err := tx.Exec(fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE((SELECT MAX(id) FROM %[1]s), 0) + 1, false)", table,
+		)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package seed
+
+import (
+	"backend/internal/model"
+	"backend/internal/testdb"
+	"context"
+	"os"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
+
+// versions retorna la versión de cada área y persona vigente, por ID
+func versions(t *testing.T, db *gorm.DB) (areas, personas map[uint]uint) {
+	t.Helper()
+	areas, personas = map[uint]uint{}, map[uint]uint{}
+
+	var areaRows []model.Area
+	if err := db.Select("id, version").Find(&areaRows).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, area := range areaRows {
+		areas[area.ID] = area.Version
+	}
+
+	var personaRows []model.Persona
+	if err := db.Select("id, version").Find(&personaRows).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, persona := range personaRows {
+		personas[persona.ID] = persona.Version
+	}
+	return areas, personas
+}
+
+// TestApplyTwice prueba que cargar dos veces el perfil test no cree ni modifique registros
+func TestApplyTwice(t *testing.T) {
+	// Arrange
+	db := testdb.Open(t)
+	ctx := context.Background()
+	fixtures, err := LoadProfile("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := Apply(ctx, db, fixtures)
+	if err != nil {
+		t.Fatalf("Se esperaba nil error en la primera carga, pero se obtuvo: %v", err)
+	}
+	areasBefore, personasBefore := versions(t, db)
+
+	// Act
+	second, err := Apply(ctx, db, fixtures)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error en la segunda carga, pero se obtuvo: %v", err)
+	}
+	if *first != (Result{Areas: 2, Personas: 3}) {
+		t.Errorf("Se esperaba crear 2 áreas y 3 personas, pero se obtuvo: %+v", *first)
+	}
+	if *second != (Result{}) {
+		t.Errorf("Se esperaba que la segunda carga no cambiara registros, pero se obtuvo: %+v", *second)
+	}
+
+	areasAfter, personasAfter := versions(t, db)
+	if len(areasAfter) != 2 || len(personasAfter) != 3 {
+		t.Errorf("Se esperaban 2 áreas y 3 personas, pero se obtuvo: %d y %d", len(areasAfter), len(personasAfter))
+	}
+	for id, version := range areasBefore {
+		if areasAfter[id] != version {
+			t.Errorf("Se esperaba que el área %d conservara la versión %d, pero se obtuvo: %d", id, version, areasAfter[id])
+		}
+	}
+	for id, version := range personasBefore {
+		if personasAfter[id] != version {
+			t.Errorf("Se esperaba que la persona %d conservara la versión %d, pero se obtuvo: %d", id, version, personasAfter[id])
+		}
+	}
+}
+
+// TestApplyAreaNameCase prueba que un área se reconozca aunque los datos cambien sus mayúsculas
+func TestApplyAreaNameCase(t *testing.T) {
+	// Arrange
+	db := testdb.Open(t)
+	ctx := context.Background()
+	if _, err := Apply(ctx, db, &Fixtures{Areas: []AreaFixture{{Nombre: "Ventas", Descripcion: "Área de ventas"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	result, err := Apply(ctx, db, &Fixtures{
+		Areas:    []AreaFixture{{Nombre: "VENTAS", Descripcion: "Ventas y posventa"}},
+		Personas: []PersonaFixture{{Nombre: "Ana", Email: "ana@test.com", Area: "ventas"}},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+	if *result != (Result{Areas: 1, Personas: 1}) {
+		t.Errorf("Se esperaba actualizar 1 área y crear 1 persona, pero se obtuvo: %+v", *result)
+	}
+
+	var areas []model.Area
+	if err := db.Find(&areas).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(areas) != 1 || areas[0].Nombre != "Ventas" || areas[0].Descripcion != "Ventas y posventa" || areas[0].Version != 2 {
+		t.Errorf("Se esperaba una sola área Ventas actualizada, pero se obtuvo: %+v", areas)
+	}
+}
+
+// TestApplyAmbiguousAreaName prueba que la carga falle si dos áreas vigentes solo difieren en mayúsculas
+func TestApplyAmbiguousAreaName(t *testing.T) {
+	// Arrange
+	db := testdb.Open(t)
+	for _, nombre := range []string{"Ventas", "ventas"} {
+		if err := db.Create(&model.Area{Nombre: nombre}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Act
+	_, err := Apply(context.Background(), db, &Fixtures{Areas: []AreaFixture{{Nombre: "VENTAS"}}})
+
+	// Assert
+	if err == nil {
+		t.Errorf("Se esperaba un error por las áreas ambiguas")
+	}
+	var count int64
+	if err := db.Model(&model.Area{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Se esperaba que no se creara ningún área, pero hay: %d", count)
+	}
+}
@@ -0,0 +1,92 @@
+package seed
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoadProfiles prueba que los perfiles incluidos en el binario sean válidos
+func TestLoadProfiles(t *testing.T) {
+	tests := []struct {
+		profile  string
+		areas    int
+		personas int
+	}{
+		{"demo", 6, 30},
+		{"test", 2, 3},
+		{"empty", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			// Act
+			fixtures, err := LoadProfile(tt.profile)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+			}
+
+			if len(fixtures.Areas) != tt.areas || len(fixtures.Personas) != tt.personas {
+				t.Errorf("Se esperaban %d áreas y %d personas, pero se obtuvo: %d y %d", tt.areas, tt.personas, len(fixtures.Areas), len(fixtures.Personas))
+			}
+		})
+	}
+}
+
+// TestLoadUnknownProfile prueba que un perfil inexistente liste los perfiles disponibles
+func TestLoadUnknownProfile(t *testing.T) {
+	// Act
+	_, err := LoadProfile("produccion")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "demo, empty, test") {
+		t.Errorf("Se esperaba un error con los perfiles disponibles, pero se obtuvo: %v", err)
+	}
+}
+
+// TestParseJSON prueba que los datos se puedan escribir en JSON
+func TestParseJSON(t *testing.T) {
+	// Arrange
+	data := []byte(`{"areas": [{"nombre": "Ventas"}], "personas": [{"nombre": "Ana", "email": "ana@test.com", "area": "ventas"}]}`)
+
+	// Act
+	fixtures, err := Parse(data, ".json")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if fixtures.Areas[0].Nombre != "Ventas" || fixtures.Personas[0].Area != "ventas" {
+		t.Errorf("Se esperaban los datos del JSON, pero se obtuvo: %+v", fixtures)
+	}
+}
+
+// TestParseRejectsInvalidFixtures prueba que se rechacen datos que no se podrían cargar de forma idempotente
+func TestParseRejectsInvalidFixtures(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		ext      string
+		expected string
+	}{
+		{"email repetido", "personas:\n  - {nombre: Ana, email: ana@test.com, area: Ventas}\n  - {nombre: Otra, email: ANA@test.com, area: Ventas}\n", ".yaml", "repetido"},
+		{"área repetida", "areas:\n  - {nombre: Ventas}\n  - {nombre: ventas}\n", ".yaml", "repetida"},
+		{"email inválido", "personas:\n  - {nombre: Ana, email: ana, area: Ventas}\n", ".yaml", "Email"},
+		{"campo desconocido", "areas:\n  - {nombre: Ventas, descripción: x}\n", ".yml", "descripción"},
+		{"formato desconocido", "", ".csv", "no soportado"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := Parse([]byte(tt.data), tt.ext)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Se esperaba un error con '%s', pero se obtuvo: %v", tt.expected, err)
+			}
+		})
+	}
+}
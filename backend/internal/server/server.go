@@ -0,0 +1,69 @@
+// Package server ejecuta el servidor HTTP y lo detiene de forma ordenada al recibir
+// SIGINT o SIGTERM, como los que envía docker compose down.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Closer libera un recurso al terminar, como el pool de conexiones a la base de datos
+type Closer struct {
+	Name  string
+	Close func() error
+}
+
+// Run atiende peticiones de srv en ln hasta recibir SIGINT o SIGTERM o hasta que se cancele ctx.
+// Entonces deja de aceptar conexiones, espera hasta shutdownTimeout a que terminen las
+// peticiones en curso y ejecuta los closers en orden, aunque el plazo se haya agotado.
+// Retorna nil si el cierre fue ordenado.
+func Run(ctx context.Context, srv *http.Server, ln net.Listener, shutdownTimeout time.Duration, closers ...Closer) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
+
+	var errs []error
+	select {
+	case err := <-serveErr:
+		// El servidor falló antes de recibir una señal; igual se liberan los recursos
+		errs = append(errs, err)
+	case <-ctx.Done():
+		// Una segunda señal termina el proceso sin esperar
+		stop()
+		slog.Info("cerrando el servidor; esperando a que terminen las peticiones en curso", "timeout", shutdownTimeout.String())
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			slog.Warn("plazo de cierre agotado; se cortan las conexiones abiertas")
+			srv.Close()
+			errs = append(errs, fmt.Errorf("cierre del servidor: %w", err))
+		} else {
+			slog.Info("peticiones en curso terminadas")
+		}
+		if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("cierre de %s: %w", closer.Name, err))
+			continue
+		}
+		slog.Info("recurso cerrado", "name", closer.Name)
+	}
+	return errors.Join(errs...)
+}
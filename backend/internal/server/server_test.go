@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startServer inicia Run con un handler que tarda delay en responder y retorna la URL
+// del servidor, un canal que se cierra cuando el handler empezó y el resultado de Run
+func startServer(t *testing.T, delay, shutdownTimeout time.Duration, closers ...Closer) (string, chan struct{}, chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(delay)
+		w.Write([]byte("ok"))
+	})}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(context.Background(), srv, ln, shutdownTimeout, closers...)
+	}()
+	return "http://" + ln.Addr().String(), started, done
+}
+
+// sendSignal envía una señal al propio proceso de los tests
+func sendSignal(t *testing.T, sig os.Signal) {
+	t.Helper()
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(sig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRunDrainsInFlightRequests prueba que SIGTERM espere a la petición en curso y cierre los recursos
+func TestRunDrainsInFlightRequests(t *testing.T) {
+	// Arrange
+	closed := false
+	url, started, done := startServer(t, 200*time.Millisecond, 5*time.Second,
+		Closer{Name: "recurso", Close: func() error { closed = true; return nil }})
+
+	response := make(chan string, 1)
+	go func() {
+		res, err := http.Get(url)
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		response <- string(body)
+	}()
+	<-started
+
+	// Act
+	sendSignal(t, syscall.SIGTERM)
+
+	// Assert
+	if body := <-response; body != "ok" {
+		t.Errorf("Se esperaba que la petición en curso terminara con 'ok', pero se obtuvo: %s", body)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Se esperaba nil error, pero se obtuvo: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Se esperaba que el servidor terminara después de la señal")
+	}
+
+	if !closed {
+		t.Errorf("Se esperaba que se cerraran los recursos al terminar")
+	}
+
+	if _, err := http.Get(url); err == nil {
+		t.Errorf("Se esperaba que el servidor no aceptara conexiones nuevas")
+	}
+}
+
+// TestRunShutdownDeadline prueba que el cierre no espere más que el plazo y que igual cierre los recursos
+func TestRunShutdownDeadline(t *testing.T) {
+	// Arrange
+	closed := false
+	url, started, done := startServer(t, 2*time.Second, 50*time.Millisecond,
+		Closer{Name: "recurso", Close: func() error { closed = true; return nil }})
+	go http.Get(url)
+	<-started
+
+	// Act
+	sendSignal(t, os.Interrupt)
+
+	// Assert
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Se esperaba context.DeadlineExceeded, pero se obtuvo: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Se esperaba que el servidor terminara al agotarse el plazo")
+	}
+
+	if !closed {
+		t.Errorf("Se esperaba que se cerraran los recursos aunque se agotara el plazo")
+	}
+}
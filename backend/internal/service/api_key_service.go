@@ -0,0 +1,136 @@
+package service
+
+import (
+	"backend/internal/authz"
+	"backend/internal/model"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// apiKeyPrefix antecede a cada API key para reconocerla, por ejemplo en un escaneo de secretos
+const apiKeyPrefix = "ak_"
+
+// apiKeyTouchInterval es el tiempo mínimo entre dos registros del último uso de una API key
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyService interface {
+	Create(ctx context.Context, request model.APIKeyRequest, createdBy uint) (*model.APIKey, string, error)
+	GetAll(ctx context.Context) ([]model.APIKey, error)
+	Revoke(ctx context.Context, id uint) (*model.APIKey, error)
+	Authenticate(ctx context.Context, key string) (*model.Principal, error)
+}
+
+type apiKeyService struct {
+	repo repository.APIKeyRepository
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{repo: repo}
+}
+
+// Create genera una API key y retorna el registro guardado junto con la clave en claro,
+// que no se puede volver a obtener
+func (s *apiKeyService) Create(ctx context.Context, request model.APIKeyRequest, createdBy uint) (*model.APIKey, string, error) {
+	scopes := make(model.ScopeList, 0, len(request.Scopes))
+	for _, scope := range request.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !authz.ValidScope(scope) {
+			return nil, "", NewValidationError("invalid_scope", "scopes", fmt.Sprintf("el scope '%s' no existe", scope))
+		}
+		scopes = append(scopes, scope)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(model.DefaultAPIKeyTTL)
+	if request.ExpiresAt != nil {
+		if !request.ExpiresAt.After(now) {
+			return nil, "", NewValidationError("invalid_expiry", "expires_at", "la fecha de expiración debe ser futura")
+		}
+		expiresAt = *request.ExpiresAt
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	plain := apiKeyPrefix + secret
+
+	key := &model.APIKey{
+		Name:      strings.TrimSpace(request.Name),
+		Prefix:    plain[:len(apiKeyPrefix)+8],
+		KeyHash:   hashToken(plain),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedBy: createdBy,
+	}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, plain, nil
+}
+
+func (s *apiKeyService) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// Revoke revoca la API key y retorna el registro actualizado. Revocar una clave ya revocada no es un error.
+func (s *apiKeyService) Revoke(ctx context.Context, id uint) (*model.APIKey, error) {
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err, errAPIKeyNotFound(), nil)
+	}
+	if key.RevokedAt != nil {
+		return key, nil
+	}
+
+	if err := s.repo.Revoke(ctx, id); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// Authenticate valida una API key y registra su uso. Si no se puede registrar el uso, el error
+// solo se registra en el log: la key sigue siendo válida y la petición no debe fallar por eso.
+func (s *apiKeyService) Authenticate(ctx context.Context, plain string) (*model.Principal, error) {
+	if !strings.HasPrefix(plain, apiKeyPrefix) {
+		return nil, errInvalidAPIKey()
+	}
+
+	key, err := s.repo.GetByHash(ctx, hashToken(plain))
+	if err != nil {
+		return nil, translateError(err, errInvalidAPIKey(), nil)
+	}
+
+	now := time.Now()
+	switch {
+	case key.RevokedAt != nil:
+		return nil, NewUnauthorizedError("api_key_revoked", "la API key fue revocada")
+	case !now.Before(key.ExpiresAt):
+		return nil, NewUnauthorizedError("api_key_expired", "la API key expiró")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			slog.WarnContext(ctx, "no se pudo registrar el uso de la API key", "api_key_id", key.ID, "error", err)
+		}
+	}
+
+	return &model.Principal{Username: "api-key:" + key.Name, APIKeyID: key.ID, Scopes: key.Scopes}, nil
+}
+
+// errAPIKeyNotFound es el error del dominio para una API key inexistente
+func errAPIKeyNotFound() *Error {
+	return NewNotFoundError("api_key_not_found", "API key no encontrada")
+}
+
+// errInvalidAPIKey es el error del dominio para una API key desconocida
+func errInvalidAPIKey() *Error {
+	return NewUnauthorizedError("invalid_api_key", "la API key no es válida")
+}
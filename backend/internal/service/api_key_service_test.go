@@ -0,0 +1,225 @@
+package service
+
+import (
+	"backend/internal/model"
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Mock del repositorio de API keys
+type mockAPIKeyRepository struct {
+	keys     []*model.APIKey
+	touches  int
+	touchErr error
+}
+
+func (m *mockAPIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	key.ID = uint(len(m.keys) + 1)
+	key.CreatedAt = time.Now()
+	m.keys = append(m.keys, key)
+	return nil
+}
+
+func (m *mockAPIKeyRepository) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	keys := make([]model.APIKey, len(m.keys))
+	for i, key := range m.keys {
+		keys[i] = *key
+	}
+	return keys, nil
+}
+
+func (m *mockAPIKeyRepository) GetByID(ctx context.Context, id uint) (*model.APIKey, error) {
+	for _, key := range m.keys {
+		if key.ID == id {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockAPIKeyRepository) GetByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, key := range m.keys {
+		if key.KeyHash == hash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockAPIKeyRepository) Revoke(ctx context.Context, id uint) error {
+	for _, key := range m.keys {
+		if key.ID == id && key.RevokedAt == nil {
+			now := time.Now()
+			key.RevokedAt = &now
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *mockAPIKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	if m.touchErr != nil {
+		return m.touchErr
+	}
+	for _, key := range m.keys {
+		if key.ID == id {
+			key.LastUsedAt = &at
+			m.touches++
+		}
+	}
+	return nil
+}
+
+// TestCreateAPIKey prueba que solo se guarde el hash de la clave y que se validen scopes y expiración
+func TestCreateAPIKey(t *testing.T) {
+	// Arrange
+	repo := &mockAPIKeyRepository{}
+	service := NewAPIKeyService(repo)
+	past := time.Now().Add(-time.Hour)
+
+	// Act
+	key, plain, err := service.Create(context.Background(), model.APIKeyRequest{Name: "reportes", Scopes: []string{"personas:read"}}, 1)
+	_, _, scopeErr := service.Create(context.Background(), model.APIKeyRequest{Name: "otra", Scopes: []string{"api-keys:manage"}}, 1)
+	_, _, expiryErr := service.Create(context.Background(), model.APIKeyRequest{Name: "vencida", Scopes: []string{"areas:read"}, ExpiresAt: &past}, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if plain == "" || key.KeyHash == plain || key.KeyHash != hashToken(plain) {
+		t.Errorf("Se esperaba que se guardara solo el hash de la clave, pero se obtuvo: %+v", key)
+	}
+
+	if key.Prefix == "" || plain[:len(key.Prefix)] != key.Prefix {
+		t.Errorf("Se esperaba que el prefijo coincidiera con el inicio de la clave, pero se obtuvo: %s", key.Prefix)
+	}
+
+	if key.ExpiresAt.Before(time.Now().Add(model.DefaultAPIKeyTTL - time.Minute)) {
+		t.Errorf("Se esperaba la expiración por defecto, pero se obtuvo: %v", key.ExpiresAt)
+	}
+
+	var domainErr *Error
+	if !errors.As(scopeErr, &domainErr) || domainErr.Code != "invalid_scope" {
+		t.Errorf("Se esperaba el error invalid_scope, pero se obtuvo: %v", scopeErr)
+	}
+
+	if !errors.As(expiryErr, &domainErr) || domainErr.Code != "invalid_expiry" {
+		t.Errorf("Se esperaba el error invalid_expiry, pero se obtuvo: %v", expiryErr)
+	}
+}
+
+// TestAuthenticateAPIKey prueba que se acepten claves vigentes, se registre su uso y se rechacen las revocadas o vencidas
+func TestAuthenticateAPIKey(t *testing.T) {
+	// Arrange
+	repo := &mockAPIKeyRepository{}
+	service := NewAPIKeyService(repo)
+	key, plain, _ := service.Create(context.Background(), model.APIKeyRequest{Name: "reportes", Scopes: []string{"personas:read"}}, 1)
+	expired, expiredPlain, _ := service.Create(context.Background(), model.APIKeyRequest{Name: "vieja", Scopes: []string{"personas:read"}}, 1)
+	repo.keys[expired.ID-1].ExpiresAt = time.Now().Add(-time.Minute)
+
+	// Act
+	principal, err := service.Authenticate(context.Background(), plain)
+	_, _ = service.Authenticate(context.Background(), plain)
+	touches := repo.touches
+	_, unknownErr := service.Authenticate(context.Background(), "ak_desconocida")
+	_, expiredErr := service.Authenticate(context.Background(), expiredPlain)
+	_, revokeErr := service.Revoke(context.Background(), key.ID)
+	_, revokedErr := service.Authenticate(context.Background(), plain)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if principal.APIKeyID != key.ID || len(principal.Scopes) != 1 || principal.Scopes[0] != "personas:read" {
+		t.Errorf("Se esperaba la API key con sus scopes, pero se obtuvo: %+v", principal)
+	}
+
+	if touches != 1 {
+		t.Errorf("Se esperaba un solo registro de uso en el mismo minuto, pero se obtuvo: %d", touches)
+	}
+
+	if revokeErr != nil {
+		t.Errorf("Se esperaba nil error al revocar, pero se obtuvo: %v", revokeErr)
+	}
+
+	for code, err := range map[string]error{"invalid_api_key": unknownErr, "api_key_expired": expiredErr, "api_key_revoked": revokedErr} {
+		var domainErr *Error
+		if !errors.As(err, &domainErr) || domainErr.Code != code || !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Se esperaba el error %s, pero se obtuvo: %v", code, err)
+		}
+	}
+}
+
+// TestRevokeAPIKeyNotFound prueba que revocar una API key inexistente retorne ErrNotFound
+func TestRevokeAPIKeyNotFound(t *testing.T) {
+	// Arrange
+	service := NewAPIKeyService(&mockAPIKeyRepository{})
+
+	// Act
+	_, err := service.Revoke(context.Background(), 99)
+
+	// Assert
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Se esperaba ErrNotFound, pero se obtuvo: %v", err)
+	}
+}
+
+// TestAuthenticateAPIKeyCanceled prueba que la consulta de la API key use el contexto de la petición
+func TestAuthenticateAPIKeyCanceled(t *testing.T) {
+	// Arrange
+	repo := &mockAPIKeyRepository{}
+	service := NewAPIKeyService(repo)
+	_, plain, _ := service.Create(context.Background(), model.APIKeyRequest{Name: "reportes", Scopes: []string{"personas:read"}}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := service.Authenticate(ctx, plain)
+
+	// Assert
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Se esperaba context.Canceled, pero se obtuvo: %v", err)
+	}
+	if repo.touches != 0 {
+		t.Errorf("Se esperaba que no se registrara el uso de la API key, pero se registró %d veces", repo.touches)
+	}
+}
+
+// TestAuthenticateAPIKeyTouchFails prueba que un error al registrar el uso no rechace una key válida
+func TestAuthenticateAPIKeyTouchFails(t *testing.T) {
+	// Arrange
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	repo := &mockAPIKeyRepository{touchErr: errors.New("connection reset by peer")}
+	service := NewAPIKeyService(repo)
+	key, plain, _ := service.Create(context.Background(), model.APIKeyRequest{Name: "reportes", Scopes: []string{"personas:read"}}, 1)
+
+	// Act
+	principal, err := service.Authenticate(context.Background(), plain)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+	if principal.APIKeyID != key.ID || principal.Username != "api-key:reportes" {
+		t.Errorf("Se esperaba el principal de la API key, pero se obtuvo: %+v", principal)
+	}
+	if !strings.Contains(logs.String(), "connection reset by peer") {
+		t.Errorf("Se esperaba el error en el log, pero se obtuvo: %s", logs.String())
+	}
+}
@@ -3,63 +3,218 @@ package service
 import (
 	"backend/internal/model"
 	"backend/internal/repository"
+	"context"
 	"errors"
-	"gorm.io/gorm"
+	"fmt"
 )
 
 type AreaService interface {
-	Create(area *model.Area) error
-	GetAll() ([]model.Area, error)
-	GetByID(id uint) (*model.Area, error)
-	Update(id uint, area *model.Area) error
-	Delete(id uint) error
-	GetAreasConConteo() ([]model.AreaConConteo, error)
+	Create(ctx context.Context, area *model.Area) error
+	GetAll(ctx context.Context, query model.AreaQuery) ([]model.Area, int64, error)
+	GetByID(ctx context.Context, id uint) (*model.Area, error)
+	Update(ctx context.Context, id uint, area *model.Area) error
+	Delete(ctx context.Context, id, version uint, opts model.AreaDeleteOptions) (int64, error)
+	Restore(ctx context.Context, id uint) (*model.Area, error)
+	GetAreasConConteo(ctx context.Context) ([]model.AreaConConteo, error)
+	MovePersonas(ctx context.Context, fromID, toID uint) (int64, error)
 }
 
 type areaService struct {
 	repo repository.AreaRepository
+	uow  repository.UnitOfWork
 }
 
-func NewAreaService(repo repository.AreaRepository) AreaService {
-	return &areaService{repo: repo}
+func NewAreaService(repo repository.AreaRepository, uow repository.UnitOfWork) AreaService {
+	return &areaService{repo: repo, uow: uow}
 }
 
-func (s *areaService) Create(area *model.Area) error {
-	return s.repo.Create(area)
+func (s *areaService) Create(ctx context.Context, area *model.Area) (err error) {
+	ctx, span := startSpan(ctx, "areaService.Create")
+	defer func() { endSpan(span, err) }()
+
+	entry := newAuditEntry(ctx, model.AuditEntityArea, 0, model.AuditCreate, nil, area.AuditFields())
+	return translateError(s.repo.Create(ctx, area, entry), nil, errAreaNombreTaken())
 }
 
-func (s *areaService) GetAll() ([]model.Area, error) {
-	return s.repo.GetAll()
+func (s *areaService) GetAll(ctx context.Context, query model.AreaQuery) ([]model.Area, int64, error) {
+	query.Normalize()
+	return s.repo.GetAll(ctx, query)
 }
 
-func (s *areaService) GetByID(id uint) (*model.Area, error) {
-	return s.repo.GetByID(id)
+func (s *areaService) GetByID(ctx context.Context, id uint) (*model.Area, error) {
+	area, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err, errAreaNotFound(), nil)
+	}
+	return area, nil
 }
 
-func (s *areaService) Update(id uint, area *model.Area) error {
-	_, err := s.repo.GetByID(id)
+// Update reemplaza los campos editables del área y deja en area el registro actualizado.
+// Los campos de gorm.Model se conservan del registro existente. Si area.Version no es
+// cero, la actualización solo se aplica sobre esa versión.
+func (s *areaService) Update(ctx context.Context, id uint, area *model.Area) (err error) {
+	ctx, span := startSpan(ctx, "areaService.Update")
+	defer func() { endSpan(span, err) }()
+
+	existingArea, err := s.GetByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("área no encontrada")
-		}
 		return err
 	}
 
-	area.ID = id
-	return s.repo.Update(area)
+	if area.Version != 0 && area.Version != existingArea.Version {
+		return errVersionMismatch()
+	}
+
+	before := existingArea.AuditFields()
+	existingArea.Nombre = area.Nombre
+	existingArea.Descripcion = area.Descripcion
+	entry := newAuditEntry(ctx, model.AuditEntityArea, id, model.AuditUpdate, before, existingArea.AuditFields())
+
+	if err := translateError(s.repo.Update(ctx, existingArea, entry), nil, errAreaNombreTaken()); err != nil {
+		return err
+	}
+
+	*area = *existingArea
+	return nil
 }
 
-func (s *areaService) Delete(id uint) error {
-	_, err := s.repo.GetByID(id)
+// Delete elimina un área aplicando la política indicada para sus personas y
+// retorna cuántas personas fueron reasignadas o eliminadas. Si version no es
+// cero, el área solo se elimina si conserva esa versión. Con opts.Hard también
+// se puede eliminar definitivamente un área que ya estaba eliminada.
+func (s *areaService) Delete(ctx context.Context, id, version uint, opts model.AreaDeleteOptions) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "areaService.Delete")
+	defer func() { endSpan(span, err) }()
+
+	var existingArea *model.Area
+	if opts.Hard {
+		existingArea, err = s.repo.GetByIDWithDeleted(ctx, id)
+		err = translateError(err, errAreaNotFound(), nil)
+	} else {
+		existingArea, err = s.GetByID(ctx, id)
+	}
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("área no encontrada")
+		return 0, err
+	}
+
+	if version != 0 && version != existingArea.Version {
+		return 0, errVersionMismatch()
+	}
+
+	switch {
+	case opts.ReassignTo != 0 && opts.Cascade:
+		return 0, NewValidationError("invalid_delete_options", "reassign_to", "no se puede usar reassign_to junto con cascade")
+	case opts.ReassignTo == id:
+		return 0, NewValidationError("invalid_reassign_target", "reassign_to", "no se puede reasignar las personas a la misma área")
+	case opts.ReassignTo != 0:
+		if _, err := s.repo.GetByID(ctx, opts.ReassignTo); err != nil {
+			return 0, translateError(err, errInvalidReassignTarget(), nil)
 		}
-		return err
 	}
-	return s.repo.Delete(id)
+
+	action := model.AuditDelete
+	if opts.Hard {
+		action = model.AuditPurge
+	}
+	entry := newAuditEntry(ctx, model.AuditEntityArea, id, action, existingArea.AuditFields(), nil)
+
+	// Las personas se cuentan dentro de la transacción del repositorio, con el área bloqueada,
+	// para que una persona asignada mientras tanto no quede en un área eliminada
+	affected, err := s.repo.Delete(ctx, id, version, opts, entry)
+	var hasPersonas *repository.AreaHasPersonasError
+	switch {
+	case errors.As(err, &hasPersonas):
+		return 0, NewConflictError("area_has_personas", fmt.Sprintf("el área tiene %d personas asignadas; use reassign_to o cascade", hasPersonas.Count))
+	case errors.Is(err, repository.ErrAreaUnavailable):
+		return 0, errInvalidReassignTarget()
+	}
+	return affected, translateError(err, errAreaNotFound(), nil)
+}
+
+// Restore revierte la eliminación de un área y retorna el registro restaurado
+func (s *areaService) Restore(ctx context.Context, id uint) (_ *model.Area, err error) {
+	ctx, span := startSpan(ctx, "areaService.Restore")
+	defer func() { endSpan(span, err) }()
+
+	deletedArea, err := s.repo.GetByIDWithDeleted(ctx, id)
+	if err != nil {
+		return nil, translateError(err, errAreaNotFound(), nil)
+	}
+
+	if !deletedArea.DeletedAt.Valid {
+		return nil, errNotDeleted("el área no está eliminada")
+	}
+
+	entry := newAuditEntry(ctx, model.AuditEntityArea, id, model.AuditRestore, nil, deletedArea.AuditFields())
+	if err := s.repo.Restore(ctx, id, entry); err != nil {
+		return nil, translateError(err, errNotDeleted("el área no está eliminada"), errAreaNombreTaken())
+	}
+	return s.GetByID(ctx, id)
+}
+
+func (s *areaService) GetAreasConConteo(ctx context.Context) ([]model.AreaConConteo, error) {
+	return s.repo.GetAreasConConteo(ctx)
+}
+
+// MovePersonas reasigna todas las personas vigentes del área fromID al área toID y retorna
+// cuántas se movieron. Las áreas se verifican y las personas se mueven en una sola transacción,
+// cada una con su entrada de auditoría: si alguna falla, por ejemplo porque otra operación la
+// modificó, no se mueve ninguna.
+func (s *areaService) MovePersonas(ctx context.Context, fromID, toID uint) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "areaService.MovePersonas")
+	defer func() { endSpan(span, err) }()
+
+	if fromID == toID {
+		return 0, errInvalidMoveTarget("no se puede mover las personas a la misma área")
+	}
+
+	var moved int64
+	err = s.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		if _, err := repos.Areas.GetByID(ctx, fromID); err != nil {
+			return translateError(err, errAreaNotFound(), nil)
+		}
+		if _, err := repos.Areas.GetByID(ctx, toID); err != nil {
+			return translateError(err, errInvalidMoveTarget("el área de destino no existe"), nil)
+		}
+
+		personas, err := repos.Personas.GetByArea(ctx, fromID)
+		if err != nil {
+			return err
+		}
+		for i := range personas {
+			persona := &personas[i]
+			before := persona.AuditFields()
+			persona.AreaID = toID
+			entry := newAuditEntry(ctx, model.AuditEntityPersona, persona.ID, model.AuditUpdate, before, persona.AuditFields())
+			if err := repos.Personas.Update(ctx, persona, entry); err != nil {
+				return translateError(err, errPersonaNotFound(), nil)
+			}
+		}
+		moved = int64(len(personas))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+// errInvalidMoveTarget es el error de validación para un área de destino inválida al mover personas
+func errInvalidMoveTarget(message string) *Error {
+	return NewValidationError("invalid_move_target", "to", message)
+}
+
+// errInvalidReassignTarget es el error de validación para un área de destino inexistente al eliminar un área
+func errInvalidReassignTarget() *Error {
+	return NewValidationError("invalid_reassign_target", "reassign_to", "el área de destino no existe")
+}
+
+// errAreaNotFound es el error del dominio para un área inexistente
+func errAreaNotFound() *Error {
+	return NewNotFoundError("area_not_found", "área no encontrada")
 }
 
-func (s *areaService) GetAreasConConteo() ([]model.AreaConConteo, error) {
-	return s.repo.GetAreasConConteo()
+// errAreaNombreTaken es el error del dominio para un nombre de área repetido
+func errAreaNombreTaken() *Error {
+	return NewConflictError("area_nombre_taken", "ya existe un área con ese nombre")
 }
@@ -2,17 +2,27 @@ package service
 
 import (
 	"backend/internal/model"
+	"backend/internal/repository"
+	"context"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 // Mock del repositorio de áreas
 type mockAreaRepository struct {
-	areas      []model.Area
-	shouldFail bool
+	areas           []model.Area
+	shouldFail      bool
+	personasPorArea map[uint]int64
+	deleted         []uint
+	updated         *model.Area
 }
 
-func (m *mockAreaRepository) Create(area *model.Area) error {
+func (m *mockAreaRepository) Create(ctx context.Context, area *model.Area, entry *model.AuditEntry) error {
 	if m.shouldFail {
 		return errors.New("database error")
 	}
@@ -21,14 +31,14 @@ func (m *mockAreaRepository) Create(area *model.Area) error {
 	return nil
 }
 
-func (m *mockAreaRepository) GetAll() ([]model.Area, error) {
+func (m *mockAreaRepository) GetAll(ctx context.Context, query model.AreaQuery) ([]model.Area, int64, error) {
 	if m.shouldFail {
-		return nil, errors.New("database error")
+		return nil, 0, errors.New("database error")
 	}
-	return m.areas, nil
+	return m.areas, int64(len(m.areas)), nil
 }
 
-func (m *mockAreaRepository) GetAreasConConteo() ([]model.AreaConConteo, error) {
+func (m *mockAreaRepository) GetAreasConConteo(ctx context.Context) ([]model.AreaConConteo, error) {
 	if m.shouldFail {
 		return nil, errors.New("database error")
 	}
@@ -41,7 +51,7 @@ func (m *mockAreaRepository) GetAreasConConteo() ([]model.AreaConConteo, error)
 	return result, nil
 }
 
-func (m *mockAreaRepository) GetByID(id uint) (*model.Area, error) {
+func (m *mockAreaRepository) GetByID(ctx context.Context, id uint) (*model.Area, error) {
 	if m.shouldFail {
 		return nil, errors.New("database error")
 	}
@@ -51,23 +61,66 @@ func (m *mockAreaRepository) GetByID(id uint) (*model.Area, error) {
 			return &area, nil
 		}
 	}
-	return nil, errors.New("area not found")
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockAreaRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*model.Area, error) {
+	return m.GetByID(ctx, id)
+}
+
+func (m *mockAreaRepository) GetExistingIDs(ctx context.Context, ids []uint) ([]uint, error) {
+	if m.shouldFail {
+		return nil, errors.New("database error")
+	}
+
+	var existing []uint
+	for _, area := range m.areas {
+		if slices.Contains(ids, area.ID) {
+			existing = append(existing, area.ID)
+		}
+	}
+	return existing, nil
+}
+
+func (m *mockAreaRepository) GetByNombre(ctx context.Context, nombre string) (*model.Area, error) {
+	if m.shouldFail {
+		return nil, errors.New("database error")
+	}
+
+	for _, area := range m.areas {
+		if strings.EqualFold(area.Nombre, nombre) {
+			return &area, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
 }
 
-func (m *mockAreaRepository) Update(area *model.Area) error {
+func (m *mockAreaRepository) Restore(ctx context.Context, id uint, entry *model.AuditEntry) error {
 	if m.shouldFail {
 		return errors.New("database error")
 	}
 	return nil
 }
 
-func (m *mockAreaRepository) Delete(id uint) error {
+func (m *mockAreaRepository) Update(ctx context.Context, area *model.Area, entry *model.AuditEntry) error {
 	if m.shouldFail {
 		return errors.New("database error")
 	}
+	m.updated = area
 	return nil
 }
 
+func (m *mockAreaRepository) Delete(ctx context.Context, id, version uint, opts model.AreaDeleteOptions, entry *model.AuditEntry) (int64, error) {
+	if m.shouldFail {
+		return 0, errors.New("database error")
+	}
+	if count := m.personasPorArea[id]; count > 0 && opts.ReassignTo == 0 && !opts.Cascade {
+		return 0, &repository.AreaHasPersonasError{Count: count}
+	}
+	m.deleted = append(m.deleted, id)
+	return m.personasPorArea[id], nil
+}
+
 // TestGetAllAreas prueba la obtención de todas las áreas
 func TestGetAllAreas(t *testing.T) {
 	// Arrange - Preparar datos de prueba
@@ -85,10 +138,10 @@ func TestGetAllAreas(t *testing.T) {
 		shouldFail: false,
 	}
 	
-	service := NewAreaService(mockRepo)
+	service := NewAreaService(mockRepo, nil)
 
 	// Act - Ejecutar la función a probar
-	areas, err := service.GetAll()
+	areas, _, err := service.GetAll(context.Background(), model.AreaQuery{})
 
 	// Assert - Verificar resultados
 	if err != nil {
@@ -119,10 +172,10 @@ func TestGetAllAreasError(t *testing.T) {
 		shouldFail: true,
 	}
 	
-	service := NewAreaService(mockRepo)
+	service := NewAreaService(mockRepo, nil)
 
 	// Act - Ejecutar la función a probar
-	areas, err := service.GetAll()
+	areas, _, err := service.GetAll(context.Background(), model.AreaQuery{})
 
 	// Assert - Verificar que se maneje el error correctamente
 	if err == nil {
@@ -145,10 +198,10 @@ func TestGetAreasConConteo(t *testing.T) {
 		shouldFail: false,
 	}
 	
-	service := NewAreaService(mockRepo)
+	service := NewAreaService(mockRepo, nil)
 
 	// Act
-	areasConteo, err := service.GetAreasConConteo()
+	areasConteo, err := service.GetAreasConConteo(context.Background())
 
 	// Assert
 	if err != nil {
@@ -175,3 +228,160 @@ func TestGetAreasConConteo(t *testing.T) {
 		t.Errorf("Se esperaban 3 personas en Marketing, pero se obtuvieron: %d", areasConteo[1].Personas)
 	}
 }
+
+// TestDeleteAreaWithPersonas prueba las políticas de eliminación de un área con personas asignadas
+func TestDeleteAreaWithPersonas(t *testing.T) {
+	// Arrange
+	area1 := model.Area{Nombre: "Ventas", Descripcion: "Área de ventas"}
+	area1.ID = 1
+
+	area2 := model.Area{Nombre: "Marketing", Descripcion: "Área de marketing"}
+	area2.ID = 2
+
+	mockRepo := &mockAreaRepository{
+		areas:           []model.Area{area1, area2},
+		shouldFail:      false,
+		personasPorArea: map[uint]int64{1: 4},
+	}
+
+	service := NewAreaService(mockRepo, nil)
+
+	// Act
+	_, refusedErr := service.Delete(context.Background(), 1, 0, model.AreaDeleteOptions{})
+	_, badTargetErr := service.Delete(context.Background(), 1, 0, model.AreaDeleteOptions{ReassignTo: 9})
+	affected, err := service.Delete(context.Background(), 1, 0, model.AreaDeleteOptions{ReassignTo: 2})
+
+	// Assert
+	if !errors.Is(refusedErr, ErrConflict) {
+		t.Errorf("Se esperaba ErrConflict, pero se obtuvo: %v", refusedErr)
+	}
+
+	if !errors.Is(badTargetErr, ErrValidation) {
+		t.Errorf("Se esperaba ErrValidation, pero se obtuvo: %v", badTargetErr)
+	}
+
+	if err != nil {
+		t.Errorf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if affected != 4 {
+		t.Errorf("Se esperaban 4 personas afectadas, pero se obtuvieron: %d", affected)
+	}
+
+	if len(mockRepo.deleted) != 1 {
+		t.Errorf("Se esperaba una sola eliminación, pero hubo: %d", len(mockRepo.deleted))
+	}
+}
+
+// TestUpdateAreaKeepsTimestamps prueba que la actualización conserve la fecha de creación
+func TestUpdateAreaKeepsTimestamps(t *testing.T) {
+	// Arrange
+	createdAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	existingArea := model.Area{Nombre: "Ventas", Descripcion: "Área de ventas"}
+	existingArea.ID = 1
+	existingArea.CreatedAt = createdAt
+
+	mockRepo := &mockAreaRepository{
+		areas:      []model.Area{existingArea},
+		shouldFail: false,
+	}
+
+	service := NewAreaService(mockRepo, nil)
+
+	area := &model.Area{Nombre: "Ventas y Clientes", Descripcion: "Nueva descripción"}
+
+	// Act
+	err := service.Update(context.Background(), 1, area)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if mockRepo.updated == nil || !mockRepo.updated.CreatedAt.Equal(createdAt) {
+		t.Errorf("Se esperaba conservar CreatedAt %v, pero se guardó: %v", createdAt, mockRepo.updated)
+	}
+
+	if area.ID != 1 || area.Nombre != "Ventas y Clientes" {
+		t.Errorf("Se esperaba el área actualizada, pero se obtuvo: %+v", area)
+	}
+}
+
+// mockUnitOfWork llama a la función con los repositorios de prueba, sin transacción
+type mockUnitOfWork struct {
+	repos repository.Repositories
+	calls int
+}
+
+func (m *mockUnitOfWork) WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	m.calls++
+	return fn(m.repos)
+}
+
+// TestMovePersonas prueba que las personas se muevan de área dentro de la unidad de trabajo
+func TestMovePersonas(t *testing.T) {
+	ventas := model.Area{Nombre: "Ventas"}
+	ventas.ID = 1
+	marketing := model.Area{Nombre: "Marketing"}
+	marketing.ID = 2
+
+	tests := []struct {
+		name         string
+		from, to     uint
+		expectedErr  error
+		expectedCode string
+		expected     int64
+	}{
+		{name: "Mueve todas las personas", from: 1, to: 2, expected: 2},
+		{name: "Misma área", from: 1, to: 1, expectedErr: ErrValidation, expectedCode: "invalid_move_target"},
+		{name: "Área de origen inexistente", from: 9, to: 2, expectedErr: ErrNotFound, expectedCode: "area_not_found"},
+		{name: "Área de destino inexistente", from: 1, to: 9, expectedErr: ErrValidation, expectedCode: "invalid_move_target"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			personas := []model.Persona{
+				{Nombre: "Ana", Email: "ana@test.com", AreaID: 1, Version: 1},
+				{Nombre: "Luis", Email: "luis@test.com", AreaID: 1, Version: 1},
+				{Nombre: "Eva", Email: "eva@test.com", AreaID: 2, Version: 1},
+			}
+			for i := range personas {
+				personas[i].ID = uint(i + 1)
+			}
+			personaRepo := &mockPersonaRepository{personas: personas}
+			areaRepo := &mockAreaRepository{areas: []model.Area{ventas, marketing}}
+			uow := &mockUnitOfWork{repos: repository.Repositories{Areas: areaRepo, Personas: personaRepo}}
+			service := NewAreaService(areaRepo, uow)
+
+			// Act
+			moved, err := service.MovePersonas(context.Background(), tt.from, tt.to)
+
+			// Assert
+			if tt.expectedErr != nil {
+				var domainErr *Error
+				if !errors.Is(err, tt.expectedErr) || !errors.As(err, &domainErr) || domainErr.Code != tt.expectedCode {
+					t.Errorf("Se esperaba el error %s, pero se obtuvo: %v", tt.expectedCode, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+			}
+			if moved != tt.expected || uow.calls != 1 {
+				t.Errorf("Se esperaban %d personas movidas en una transacción, pero se obtuvo: %d en %d", tt.expected, moved, uow.calls)
+			}
+			if len(personaRepo.audit) != 2 {
+				t.Fatalf("Se esperaban 2 entradas de auditoría, pero se obtuvo: %d", len(personaRepo.audit))
+			}
+			for _, entry := range personaRepo.audit {
+				change, ok := entry.Changes["area_id"]
+				if entry.Action != model.AuditUpdate || !ok || change.After != uint(2) {
+					t.Errorf("Se esperaba el cambio de area_id a 2, pero se obtuvo: %+v", entry)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/repository"
+	"context"
+)
+
+// auditSystemActor es el autor de las operaciones que no provienen de una petición autenticada
+const auditSystemActor = "system"
+
+type AuditService interface {
+	GetAll(ctx context.Context, query model.AuditQuery) ([]model.AuditEntry, int64, error)
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+// GetAll obtiene las entradas de auditoría; filtrar por ID requiere indicar la entidad
+func (s *auditService) GetAll(ctx context.Context, query model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	if query.EntityID != 0 && query.Entity == "" {
+		return nil, 0, NewValidationError("entity_required", "entity", "para filtrar por id se debe indicar la entidad")
+	}
+	query.Normalize()
+	return s.repo.GetAll(ctx, query)
+}
+
+// newAuditEntry arma la entrada de auditoría de una operación con el autor que viaja en ctx.
+// before y after son los campos auditados del registro; nil si no existía o ya no existe.
+func newAuditEntry(ctx context.Context, entity model.AuditEntity, id uint, action model.AuditAction, before, after map[string]interface{}) *model.AuditEntry {
+	entry := &model.AuditEntry{
+		Entity:   entity,
+		EntityID: id,
+		Action:   action,
+		Actor:    auditSystemActor,
+		Changes:  model.NewAuditChanges(before, after),
+	}
+
+	if principal := model.PrincipalFromContext(ctx); principal != nil {
+		entry.Actor = principal.Username
+		if userID := principal.UserID; userID != 0 {
+			entry.UserID = &userID
+		}
+		if apiKeyID := principal.APIKeyID; apiKeyID != 0 {
+			entry.APIKeyID = &apiKeyID
+		}
+	}
+	return entry
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// MinPasswordLength es el largo mínimo de la contraseña de un usuario
+const MinPasswordLength = 8
+
+// AuthConfig contiene la clave de firma y la duración de los tokens
+type AuthConfig struct {
+	Secret     []byte
+	Issuer     string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+type AuthService interface {
+	Login(ctx context.Context, username, password string) (*model.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*model.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	Authenticate(accessToken string) (*model.Principal, error)
+	EnsureUser(ctx context.Context, username, password string, role model.Role) error
+}
+
+type authService struct {
+	users  repository.UserRepository
+	tokens repository.RefreshTokenRepository
+	config AuthConfig
+}
+
+func NewAuthService(users repository.UserRepository, tokens repository.RefreshTokenRepository, config AuthConfig) AuthService {
+	return &authService{users: users, tokens: tokens, config: config}
+}
+
+// accessClaims son los claims del token de acceso; el subject es el ID del usuario.
+// Un cambio de rol o de área se refleja al renovar la sesión.
+type accessClaims struct {
+	Username string     `json:"username"`
+	Role     model.Role `json:"role"`
+	AreaID   uint       `json:"area_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// dummyPasswordHash se compara cuando el usuario no existe, para que la respuesta tarde lo mismo
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+// Login verifica las credenciales e inicia una nueva sesión
+func (s *authService) Login(ctx context.Context, username, password string) (*model.TokenPair, error) {
+	user, err := s.users.GetByUsername(ctx, username)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, errInvalidCredentials()
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, errInvalidCredentials()
+	}
+
+	family, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, refresh, err := s.issue(user, family)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokens.Create(ctx, refresh); err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Refresh canjea un token de renovación por un par nuevo y revoca el usado.
+// Si se presenta un token ya usado se revoca toda la sesión, porque pudo haber sido robado.
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*model.TokenPair, error) {
+	current, err := s.tokens.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, translateError(err, errInvalidRefreshToken(), nil)
+	}
+
+	if current.RevokedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, current.Family)
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, NewUnauthorizedError("refresh_token_expired", "la sesión expiró; vuelva a iniciar sesión")
+	}
+
+	user, err := s.users.GetByID(ctx, current.UserID)
+	if err != nil {
+		return nil, translateError(err, errInvalidRefreshToken(), nil)
+	}
+
+	pair, next, err := s.issue(user, current.Family)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokens.Rotate(ctx, current, next); err != nil {
+		if errors.Is(err, repository.ErrTokenRevoked) {
+			return nil, s.revokeReusedFamily(ctx, current.Family)
+		}
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Logout cierra la sesión del token de renovación. Un token desconocido o ya revocado no es un error.
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	current, err := s.tokens.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.tokens.RevokeFamily(ctx, current.Family)
+}
+
+// Authenticate valida la firma, el emisor y la expiración de un token de acceso
+func (s *authService) Authenticate(accessToken string) (*model.Principal, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(accessToken, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.config.Secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.config.Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, &Error{Kind: ErrUnauthorized, Code: "invalid_token", Message: "el token de acceso no es válido o expiró", Err: err}
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return nil, NewUnauthorizedError("invalid_token", "el token de acceso no es válido o expiró")
+	}
+	return &model.Principal{UserID: uint(userID), Username: claims.Username, Role: claims.Role, AreaID: claims.AreaID}, nil
+}
+
+// EnsureUser crea el usuario con la contraseña y el rol indicados si todavía no existe
+func (s *authService) EnsureUser(ctx context.Context, username, password string, role model.Role) error {
+	if !role.Valid() || role == model.RoleAreaManager {
+		return NewValidationError("invalid_role", "role", fmt.Sprintf("el rol '%s' no es válido para un usuario sin área", role))
+	}
+
+	if _, err := s.users.GetByUsername(ctx, username); err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.users.Create(ctx, &model.User{Username: username, PasswordHash: hash, Role: role})
+}
+
+// hashPassword verifica el largo mínimo de la contraseña y retorna su hash bcrypt
+func hashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", NewValidationError("password_too_short", "password", "la contraseña debe tener al menos 8 caracteres")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// issue firma un token de acceso y genera el token de renovación de la sesión family
+func (s *authService) issue(user *model.User, family string) (*model.TokenPair, *model.RefreshToken, error) {
+	now := time.Now()
+	jti, err := randomToken(16)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claims := accessClaims{
+		Username: user.Username,
+		Role:     user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			Issuer:    s.config.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.AccessTTL)),
+			ID:        jti,
+		},
+	}
+	if user.AreaID != nil {
+		claims.AreaID = *user.AreaID
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.config.Secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pair := &model.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.config.AccessTTL.Seconds()),
+	}
+	refresh := &model.RefreshToken{
+		UserID:    user.ID,
+		Family:    family,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.config.RefreshTTL),
+	}
+	return pair, refresh, nil
+}
+
+// revokeReusedFamily revoca la sesión de un token reutilizado y retorna el error correspondiente
+func (s *authService) revokeReusedFamily(ctx context.Context, family string) error {
+	if err := s.tokens.RevokeFamily(ctx, family); err != nil {
+		return err
+	}
+	return NewUnauthorizedError("refresh_token_reused", "el token de renovación ya fue usado; la sesión se cerró por seguridad")
+}
+
+// randomToken genera un valor aleatorio de size bytes codificado en base64 URL
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken obtiene el hash con el que se guarda un token de renovación
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// errInvalidCredentials es el error del dominio para un usuario o contraseña incorrectos
+func errInvalidCredentials() *Error {
+	return NewUnauthorizedError("invalid_credentials", "usuario o contraseña incorrectos")
+}
+
+// errInvalidRefreshToken es el error del dominio para un token de renovación desconocido
+func errInvalidRefreshToken() *Error {
+	return NewUnauthorizedError("invalid_refresh_token", "el token de renovación no es válido")
+}
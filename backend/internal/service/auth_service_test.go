@@ -0,0 +1,215 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Mock del repositorio de usuarios
+type mockUserRepository struct {
+	users []model.User
+}
+
+func (m *mockUserRepository) Create(ctx context.Context, user *model.User) error {
+	user.ID = uint(len(m.users) + 1)
+	m.users = append(m.users, *user)
+	return nil
+}
+
+func (m *mockUserRepository) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockUserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	for _, user := range m.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Mock del repositorio de tokens de renovación
+type mockRefreshTokenRepository struct {
+	tokens []*model.RefreshToken
+}
+
+func (m *mockRefreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	token.ID = uint(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *mockRefreshTokenRepository) GetByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockRefreshTokenRepository) Rotate(ctx context.Context, old *model.RefreshToken, next *model.RefreshToken) error {
+	if err := m.Revoke(ctx, old.ID); err != nil {
+		return err
+	}
+	return m.Create(ctx, next)
+}
+
+func (m *mockRefreshTokenRepository) Revoke(ctx context.Context, id uint) error {
+	for _, token := range m.tokens {
+		if token.ID == id && token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+			return nil
+		}
+	}
+	return repository.ErrTokenRevoked
+}
+
+func (m *mockRefreshTokenRepository) RevokeFamily(ctx context.Context, family string) error {
+	for _, token := range m.tokens {
+		if token.Family == family && token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// newTestAuthService crea un servicio de autenticación con el usuario "admin" y contraseña "secreto123"
+func newTestAuthService(t *testing.T) (AuthService, *mockRefreshTokenRepository) {
+	t.Helper()
+
+	tokens := &mockRefreshTokenRepository{}
+	service := NewAuthService(&mockUserRepository{}, tokens, AuthConfig{
+		Secret:     []byte("clave-de-prueba-de-32-caracteres!"),
+		Issuer:     "test",
+		AccessTTL:  time.Minute,
+		RefreshTTL: time.Hour,
+	})
+
+	if err := service.EnsureUser(context.Background(), "admin", "secreto123", model.RoleAdmin); err != nil {
+		t.Fatalf("Error al crear el usuario de prueba: %v", err)
+	}
+	return service, tokens
+}
+
+// TestLogin prueba el inicio de sesión con credenciales válidas e inválidas
+func TestLogin(t *testing.T) {
+	// Arrange
+	service, _ := newTestAuthService(t)
+
+	// Act
+	tokens, err := service.Login(context.Background(), "admin", "secreto123")
+	_, wrongPasswordErr := service.Login(context.Background(), "admin", "otra-clave")
+	_, unknownUserErr := service.Login(context.Background(), "nadie", "secreto123")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	principal, err := service.Authenticate(tokens.AccessToken)
+	if err != nil || principal.Username != "admin" || principal.UserID != 1 || principal.Role != model.RoleAdmin {
+		t.Errorf("Se esperaba el token de acceso del usuario admin, pero se obtuvo: %+v (%v)", principal, err)
+	}
+
+	if !errors.Is(wrongPasswordErr, ErrUnauthorized) || !errors.Is(unknownUserErr, ErrUnauthorized) {
+		t.Errorf("Se esperaba ErrUnauthorized, pero se obtuvo: %v y %v", wrongPasswordErr, unknownUserErr)
+	}
+}
+
+// TestRefreshRotatesToken prueba que el token de renovación se rota y que reutilizarlo cierra la sesión
+func TestRefreshRotatesToken(t *testing.T) {
+	// Arrange
+	service, tokens := newTestAuthService(t)
+	first, _ := service.Login(context.Background(), "admin", "secreto123")
+
+	// Act
+	second, err := service.Refresh(context.Background(), first.RefreshToken)
+	_, reusedErr := service.Refresh(context.Background(), first.RefreshToken)
+	_, revokedErr := service.Refresh(context.Background(), second.RefreshToken)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if second.RefreshToken == first.RefreshToken {
+		t.Errorf("Se esperaba un token de renovación nuevo")
+	}
+
+	var domainErr *Error
+	if !errors.As(reusedErr, &domainErr) || domainErr.Code != "refresh_token_reused" {
+		t.Errorf("Se esperaba el error refresh_token_reused, pero se obtuvo: %v", reusedErr)
+	}
+
+	if !errors.Is(revokedErr, ErrUnauthorized) {
+		t.Errorf("Se esperaba que la sesión quedara revocada, pero se obtuvo: %v", revokedErr)
+	}
+
+	for _, token := range tokens.tokens {
+		if token.RevokedAt == nil {
+			t.Errorf("Se esperaba que todos los tokens de la sesión estuvieran revocados")
+		}
+	}
+}
+
+// TestLogout prueba que cerrar sesión invalida el token de renovación
+func TestLogout(t *testing.T) {
+	// Arrange
+	service, _ := newTestAuthService(t)
+	tokens, _ := service.Login(context.Background(), "admin", "secreto123")
+
+	// Act
+	err := service.Logout(context.Background(), tokens.RefreshToken)
+	_, refreshErr := service.Refresh(context.Background(), tokens.RefreshToken)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if !errors.Is(refreshErr, ErrUnauthorized) {
+		t.Errorf("Se esperaba ErrUnauthorized, pero se obtuvo: %v", refreshErr)
+	}
+}
+
+// TestAuthenticateRejectsInvalidTokens prueba que se rechacen tokens alterados o firmados con otra clave
+func TestAuthenticateRejectsInvalidTokens(t *testing.T) {
+	// Arrange
+	service, _ := newTestAuthService(t)
+	tokens, _ := service.Login(context.Background(), "admin", "secreto123")
+
+	other := NewAuthService(&mockUserRepository{users: []model.User{{Username: "admin"}}}, &mockRefreshTokenRepository{}, AuthConfig{
+		Secret:    []byte("otra-clave-de-prueba-de-32-caracteres"),
+		Issuer:    "test",
+		AccessTTL: time.Minute,
+	})
+
+	// Act
+	_, tamperedErr := service.Authenticate(tokens.AccessToken + "x")
+	_, otherKeyErr := other.Authenticate(tokens.AccessToken)
+
+	// Assert
+	if !errors.Is(tamperedErr, ErrUnauthorized) {
+		t.Errorf("Se esperaba ErrUnauthorized para un token alterado, pero se obtuvo: %v", tamperedErr)
+	}
+
+	if !errors.Is(otherKeyErr, ErrUnauthorized) {
+		t.Errorf("Se esperaba ErrUnauthorized para otra clave, pero se obtuvo: %v", otherKeyErr)
+	}
+}
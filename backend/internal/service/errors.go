@@ -0,0 +1,112 @@
+package service
+
+import (
+	"backend/internal/repository"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Categorías de error del dominio. Se comparan con errors.Is para decidir cómo responder.
+var (
+	ErrNotFound     = errors.New("recurso no encontrado")
+	ErrConflict     = errors.New("conflicto con el estado actual")
+	ErrValidation   = errors.New("datos inválidos")
+	ErrForeignKey   = errors.New("referencia inválida")
+	ErrPrecondition = errors.New("la versión del registro no coincide")
+	ErrUnauthorized = errors.New("no autenticado")
+	ErrForbidden    = errors.New("operación no permitida")
+)
+
+// Error es un error del dominio con un código estable para los clientes
+type Error struct {
+	Kind    error
+	Code    string
+	Field   string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is permite comparar el error con su categoría usando errors.Is
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap expone el error original, si existe
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewNotFoundError crea un error para un recurso inexistente
+func NewNotFoundError(code, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+// NewConflictError crea un error para una operación que choca con el estado actual
+func NewConflictError(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+// NewValidationError crea un error de validación asociado a un campo
+func NewValidationError(code, field, message string) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Field: field, Message: message}
+}
+
+// NewForeignKeyError crea un error para una referencia a otro registro que no es válida
+func NewForeignKeyError(code, field, message string) *Error {
+	return &Error{Kind: ErrForeignKey, Code: code, Field: field, Message: message}
+}
+
+// NewPreconditionError crea un error para una versión esperada que no coincide con la actual
+func NewPreconditionError(code, message string) *Error {
+	return &Error{Kind: ErrPrecondition, Code: code, Message: message}
+}
+
+// NewUnauthorizedError crea un error para una petición sin credenciales válidas
+func NewUnauthorizedError(code, message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+// NewForbiddenError crea un error para una operación que el usuario no tiene permitida
+func NewForbiddenError(code, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+// errVersionMismatch es el error del dominio para una actualización sobre una versión desactualizada
+func errVersionMismatch() *Error {
+	return NewPreconditionError("version_mismatch", "el registro fue modificado por otra operación; vuelva a obtenerlo")
+}
+
+// errNotDeleted es el error del dominio para restaurar un registro que no está eliminado
+func errNotDeleted(message string) *Error {
+	return NewConflictError("not_deleted", message)
+}
+
+// translateError convierte los errores conocidos de GORM y de los repositorios en errores del dominio
+func translateError(err error, notFound, conflict *Error) error {
+	switch {
+	case err == nil:
+		return nil
+	case notFound != nil && errors.Is(err, gorm.ErrRecordNotFound):
+		notFound.Err = err
+		return notFound
+	case conflict != nil && errors.Is(err, gorm.ErrDuplicatedKey):
+		conflict.Err = err
+		return conflict
+	case errors.Is(err, repository.ErrVersionConflict):
+		versionErr := errVersionMismatch()
+		versionErr.Err = err
+		return versionErr
+	case errors.Is(err, repository.ErrAreaUnavailable):
+		areaErr := errInvalidArea()
+		areaErr.Err = err
+		return areaErr
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return &Error{Kind: ErrForeignKey, Code: "foreign_key_violation", Message: "la operación hace referencia a un registro inexistente o en uso", Err: err}
+	}
+	return err
+}
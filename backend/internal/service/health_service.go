@@ -0,0 +1,93 @@
+package service
+
+import (
+	"backend/internal/model"
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+)
+
+// Nombres de los chequeos de disponibilidad
+const (
+	checkDatabase   = "database"
+	checkMigrations = "migrations"
+)
+
+// checkErrors son los mensajes que se exponen cuando falla cada chequeo. /readyz y /health son
+// públicas: el error original, que puede incluir direcciones o nombres de la base, solo se registra.
+var checkErrors = map[string]string{
+	checkDatabase:   "la base de datos no responde",
+	checkMigrations: "el esquema de la base de datos no está al día",
+}
+
+// Pinger es la conexión a la base de datos que se chequea; *sql.DB la implementa
+type Pinger interface {
+	PingContext(ctx context.Context) error
+	Stats() sql.DBStats
+}
+
+// MigrationChecker verifica que el esquema esté al día; *migrate.Migrator lo implementa
+type MigrationChecker interface {
+	Check(ctx context.Context) error
+}
+
+type HealthService interface {
+	Ready(ctx context.Context) *model.Readiness
+	Build() model.BuildInfo
+}
+
+type healthService struct {
+	db         Pinger
+	migrations MigrationChecker
+	timeout    time.Duration
+	build      model.BuildInfo
+}
+
+// NewHealthService crea el servicio de salud; timeout limita la duración total de los chequeos
+func NewHealthService(db Pinger, migrations MigrationChecker, timeout time.Duration, build model.BuildInfo) HealthService {
+	return &healthService{db: db, migrations: migrations, timeout: timeout, build: build}
+}
+
+// Ready verifica que la base responda y que tenga aplicadas las migraciones del binario.
+// Las migraciones no se chequean si la base no responde. Un chequeo fallido se informa con un
+// mensaje fijo y su error se registra en el log. También informa el uso del pool de conexiones.
+func (s *healthService) Ready(ctx context.Context) *model.Readiness {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	readiness := &model.Readiness{Status: model.HealthOK, Checks: map[string]model.HealthCheck{}}
+	check := func(name string, fn func(ctx context.Context) error) bool {
+		start := time.Now()
+		err := fn(ctx)
+		result := model.HealthCheck{Status: model.HealthOK, DurationMs: time.Since(start).Milliseconds()}
+		if err != nil {
+			result.Status = model.HealthFail
+			result.Error = checkErrors[name]
+			readiness.Status = model.HealthDegraded
+			slog.WarnContext(ctx, "chequeo de disponibilidad fallido", "check", name, "error", err)
+		}
+		readiness.Checks[name] = result
+		return err == nil
+	}
+
+	if check(checkDatabase, s.db.PingContext) {
+		check(checkMigrations, s.migrations.Check)
+	} else {
+		readiness.Checks[checkMigrations] = model.HealthCheck{Status: model.HealthFail, Error: "no se chequeó porque la base de datos no responde"}
+	}
+
+	stats := s.db.Stats()
+	readiness.Pool = model.PoolStats{
+		OpenConnections: stats.OpenConnections,
+		InUse:           stats.InUse,
+		Idle:            stats.Idle,
+		WaitCount:       stats.WaitCount,
+	}
+	return readiness
+}
+
+// Build retorna la versión, el commit y el inicio del proceso
+func (s *healthService) Build() model.BuildInfo {
+	return s.build
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"backend/internal/model"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Mock de la conexión a la base de datos
+type mockPinger struct {
+	err   error
+	delay time.Duration
+	stats sql.DBStats
+}
+
+func (m *mockPinger) PingContext(ctx context.Context) error {
+	select {
+	case <-time.After(m.delay):
+		return m.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *mockPinger) Stats() sql.DBStats {
+	return m.stats
+}
+
+// Mock del verificador de migraciones
+type mockMigrationChecker struct {
+	err     error
+	checked bool
+}
+
+func (m *mockMigrationChecker) Check(ctx context.Context) error {
+	m.checked = true
+	return m.err
+}
+
+// TestReady prueba el estado de disponibilidad según las dependencias
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		db         *mockPinger
+		migrations *mockMigrationChecker
+		status     model.HealthStatus
+		failed     []string
+	}{
+		{"todo disponible", &mockPinger{}, &mockMigrationChecker{}, model.HealthOK, nil},
+		{"base caída", &mockPinger{err: errors.New("dial tcp 10.0.0.5:5432: connection refused")}, &mockMigrationChecker{}, model.HealthDegraded, []string{checkDatabase, checkMigrations}},
+		{"base lenta", &mockPinger{delay: time.Second}, &mockMigrationChecker{}, model.HealthDegraded, []string{checkDatabase, checkMigrations}},
+		{"migraciones pendientes", &mockPinger{}, &mockMigrationChecker{err: errors.New("hay 1 migraciones pendientes en 10.0.0.5")}, model.HealthDegraded, []string{checkMigrations}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := NewHealthService(tt.db, tt.migrations, 50*time.Millisecond, model.BuildInfo{Version: "1.0.0"})
+
+			// Act
+			readiness := service.Ready(context.Background())
+
+			// Assert
+			if readiness.Status != tt.status {
+				t.Errorf("Se esperaba el estado %s, pero se obtuvo: %s", tt.status, readiness.Status)
+			}
+
+			for _, name := range tt.failed {
+				if check := readiness.Checks[name]; check.Status != model.HealthFail || check.Error == "" {
+					t.Errorf("Se esperaba que el chequeo %s fallara con detalle, pero se obtuvo: %+v", name, check)
+				} else if strings.Contains(check.Error, "10.0.0.5") {
+					t.Errorf("Se esperaba un mensaje fijo sin el error original, pero se obtuvo: %s", check.Error)
+				}
+			}
+		})
+	}
+}
+
+// TestReadyPoolStats prueba que la disponibilidad informe las cantidades del pool de conexiones
+func TestReadyPoolStats(t *testing.T) {
+	// Arrange
+	db := &mockPinger{stats: sql.DBStats{OpenConnections: 5, InUse: 2, Idle: 3, WaitCount: 7, MaxOpenConnections: 10}}
+	service := NewHealthService(db, &mockMigrationChecker{}, time.Second, model.BuildInfo{})
+
+	// Act
+	readiness := service.Ready(context.Background())
+
+	// Assert
+	expected := model.PoolStats{OpenConnections: 5, InUse: 2, Idle: 3, WaitCount: 7}
+	if readiness.Pool != expected {
+		t.Errorf("Se esperaba el pool %+v, pero se obtuvo: %+v", expected, readiness.Pool)
+	}
+}
+
+// TestReadySkipsMigrationsWhenDatabaseIsDown prueba que no se consulten las migraciones sin base de datos
+func TestReadySkipsMigrationsWhenDatabaseIsDown(t *testing.T) {
+	// Arrange
+	migrations := &mockMigrationChecker{}
+	service := NewHealthService(&mockPinger{err: errors.New("connection refused")}, migrations, time.Second, model.BuildInfo{})
+
+	// Act
+	service.Ready(context.Background())
+
+	// Assert
+	if migrations.checked {
+		t.Errorf("Se esperaba que no se chequearan las migraciones con la base caída")
+	}
+}
+
+// TestReadyLogsCheckErrors prueba que el error original de un chequeo fallido se registre en el log
+func TestReadyLogsCheckErrors(t *testing.T) {
+	// Arrange
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	service := NewHealthService(&mockPinger{err: errors.New("dial tcp 10.0.0.5:5432: connection refused")}, &mockMigrationChecker{}, time.Second, model.BuildInfo{})
+
+	// Act
+	readiness := service.Ready(context.Background())
+
+	// Assert
+	if readiness.Checks[checkDatabase].Error != checkErrors[checkDatabase] {
+		t.Errorf("Se esperaba el mensaje '%s', pero se obtuvo: %s", checkErrors[checkDatabase], readiness.Checks[checkDatabase].Error)
+	}
+	if !strings.Contains(logs.String(), "check=database") || !strings.Contains(logs.String(), "10.0.0.5:5432") {
+		t.Errorf("Se esperaba el error original en el log, pero se obtuvo: %s", logs.String())
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/repository"
+	"backend/internal/testdb"
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// Los tests de este archivo usan un Postgres real; el resto del paquete usa mocks
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
+
+// staleEmailRepository simula que la verificación previa del email perdió la carrera:
+// GetByEmail nunca encuentra a la persona, de modo que solo la base puede detectar el conflicto
+type staleEmailRepository struct {
+	repository.PersonaRepository
+}
+
+func (r staleEmailRepository) GetByEmail(ctx context.Context, email string) (*model.Persona, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r staleEmailRepository) GetExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	return nil, nil
+}
+
+// setupEmailTest crea un área en la base de los tests y retorna sus repositorios
+func setupEmailTest(t *testing.T) (repository.PersonaRepository, repository.AreaRepository, uint) {
+	t.Helper()
+	db := testdb.Open(t)
+	personaRepo := repository.NewPersonaRepository(db)
+	areaRepo := repository.NewAreaRepository(db)
+
+	area := &model.Area{Nombre: "Ventas"}
+	if err := areaRepo.Create(context.Background(), area, nil); err != nil {
+		t.Fatal(err)
+	}
+	return personaRepo, areaRepo, area.ID
+}
+
+// assertEmailTaken verifica que err sea el conflicto del dominio por email repetido
+func assertEmailTaken(t *testing.T, err error) {
+	t.Helper()
+	var domainErr *Error
+	if !errors.Is(err, ErrConflict) || !errors.As(err, &domainErr) || domainErr.Code != "email_taken" {
+		t.Errorf("Se esperaba el conflicto email_taken, pero se obtuvo: %v", err)
+	}
+}
+
+// TestCreatePersonaConcurrentEmail prueba que de varios registros simultáneos del mismo email,
+// escrito con distintas mayúsculas, solo uno se cree y el resto reciba el conflicto del dominio
+func TestCreatePersonaConcurrentEmail(t *testing.T) {
+	// Arrange
+	personaRepo, areaRepo, areaID := setupEmailTest(t)
+	service := NewPersonaService(personaRepo, areaRepo)
+	emails := []string{"juan@x.com", "Juan@X.com", " JUAN@x.com ", "juan@X.COM"}
+
+	const workers = 12
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, workers)
+	)
+
+	// Act
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = service.Create(context.Background(), &model.Persona{Nombre: "Juan", Email: emails[i%len(emails)], AreaID: areaID})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	// Assert
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assertEmailTaken(t, err)
+	}
+	if created != 1 {
+		t.Errorf("Se esperaba que se creara 1 persona, pero se crearon: %d", created)
+	}
+
+	persona, err := service.GetByEmail(context.Background(), "JUAN@x.com")
+	if err != nil || persona.Email != "juan@x.com" {
+		t.Errorf("Se esperaba la persona con el email normalizado, pero se obtuvo: %+v, %v", persona, err)
+	}
+}
+
+// TestCreatePersonaEmailUniqueIndex prueba que el índice único detecte el email repetido sin
+// distinguir mayúsculas aunque la verificación previa no lo encuentre, y que su error se
+// traduzca al conflicto del dominio
+func TestCreatePersonaEmailUniqueIndex(t *testing.T) {
+	// Arrange
+	personaRepo, areaRepo, areaID := setupEmailTest(t)
+	if err := personaRepo.Create(context.Background(), &model.Persona{Nombre: "Juan", Email: "juan@x.com", AreaID: areaID}, nil); err != nil {
+		t.Fatal(err)
+	}
+	service := NewPersonaService(staleEmailRepository{personaRepo}, areaRepo)
+
+	// Act
+	err := service.Create(context.Background(), &model.Persona{Nombre: "Otro Juan", Email: "Juan@X.com", AreaID: areaID})
+
+	// Assert
+	assertEmailTaken(t, err)
+}
+
+// TestImportPersonasExistingDataPostgres prueba que la importación detecte con sus consultas por
+// conjunto los emails registrados, aunque difieran en mayúsculas, y las áreas eliminadas
+func TestImportPersonasExistingDataPostgres(t *testing.T) {
+	// Arrange
+	personaRepo, areaRepo, areaID := setupEmailTest(t)
+	ctx := context.Background()
+	if err := personaRepo.Create(ctx, &model.Persona{Nombre: "Juan", Email: "Juan@X.com", AreaID: areaID}, nil); err != nil {
+		t.Fatal(err)
+	}
+	deleted := &model.Area{Nombre: "Marketing"}
+	if err := areaRepo.Create(ctx, deleted, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := areaRepo.Delete(ctx, deleted.ID, 0, model.AreaDeleteOptions{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	service := NewPersonaService(personaRepo, areaRepo)
+
+	rows := []model.ImportRow{
+		{Line: 2, Persona: model.Persona{Nombre: "Juan", Email: "juan@x.com", AreaID: areaID}},
+		{Line: 3, Persona: model.Persona{Nombre: "Eva", Email: "eva@x.com", AreaID: deleted.ID}},
+		{Line: 4, Persona: model.Persona{Nombre: "Ana", Email: "ana@x.com", AreaID: areaID}},
+	}
+
+	// Act
+	report, err := service.Import(ctx, rows, model.ImportOptions{Mode: model.ImportBestEffort})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+	if report.Rows[0].Code != "email_taken" || report.Rows[1].Code != "invalid_area" || report.Rows[2].Status != model.ImportRowCreated {
+		t.Errorf("Se esperaba el email registrado, el área eliminada y una persona creada, pero se obtuvo: %+v", report.Rows)
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// MaxImportRows es la cantidad máxima de filas aceptadas en una importación
+const MaxImportRows = 5000
+
+// Import valida cada fila con las mismas reglas que Create y, salvo en dry run, crea las personas.
+// En modo atómico se crean todas en una transacción o ninguna; en best effort se crean las válidas.
+// Los errores de cada fila van en el reporte; solo se retorna error si la importación no pudo completarse.
+func (s *personaService) Import(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions) (_ *model.ImportReport, err error) {
+	ctx, span := startSpan(ctx, "personaService.Import",
+		attribute.Int("import.rows", len(rows)), attribute.String("import.mode", string(opts.Mode)))
+	defer func() { endSpan(span, err) }()
+
+	if opts.Mode == "" {
+		opts.Mode = model.ImportAtomic
+	}
+	if len(rows) == 0 {
+		return nil, NewValidationError("empty_import", "", "el archivo no contiene filas")
+	}
+	if len(rows) > MaxImportRows {
+		return nil, NewValidationError("import_too_large", "", fmt.Sprintf("se aceptan como máximo %d filas por importación", MaxImportRows))
+	}
+
+	report := &model.ImportReport{
+		Mode:   opts.Mode,
+		DryRun: opts.DryRun,
+		Total:  len(rows),
+		Rows:   make([]model.ImportRowResult, len(rows)),
+	}
+
+	personas := make([]*model.Persona, len(rows))
+	areaIDs := map[string]uint{}
+	emails := map[string]int{}
+
+	for i := range rows {
+		row := &rows[i]
+		report.Rows[i] = model.ImportRowResult{Line: row.Line, Email: row.Persona.Email}
+
+		err := s.validateImportRow(ctx, row, areaIDs, emails)
+		if err != nil {
+			var domainErr *Error
+			if !errors.As(err, &domainErr) {
+				return nil, err
+			}
+			setRowError(&report.Rows[i], domainErr)
+			continue
+		}
+
+		personas[i] = &row.Persona
+	}
+
+	if err := s.checkImportConflicts(ctx, report, personas); err != nil {
+		return nil, err
+	}
+	for i, persona := range personas {
+		if persona != nil {
+			report.Rows[i].Status = model.ImportRowValid
+			report.Valid++
+		}
+	}
+	report.Failed = report.Total - report.Valid
+
+	switch {
+	case opts.DryRun:
+		return report, nil
+	case opts.Mode == model.ImportAtomic:
+		return report, s.importAtomic(ctx, report, personas)
+	default:
+		return report, s.importBestEffort(ctx, report, personas)
+	}
+}
+
+// validateImportRow resuelve el área de la fila y aplica las validaciones de Create que no
+// consultan la base, incluyendo los emails repetidos dentro del mismo archivo
+func (s *personaService) validateImportRow(ctx context.Context, row *model.ImportRow, areaIDs map[string]uint, emails map[string]int) error {
+	if row.ParseError != "" {
+		return NewValidationError("invalid_row", "", row.ParseError)
+	}
+
+	if row.Persona.AreaID == 0 && row.AreaNombre != "" {
+		areaID, ok := areaIDs[row.AreaNombre]
+		if !ok {
+			area, err := s.areaRepo.GetByNombre(ctx, row.AreaNombre)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return NewValidationError("invalid_area", "area", fmt.Sprintf("no existe un área llamada '%s'", row.AreaNombre))
+				}
+				return err
+			}
+			areaID = area.ID
+			areaIDs[row.AreaNombre] = areaID
+		}
+		row.Persona.AreaID = areaID
+	}
+
+	row.Persona.Email = model.NormalizeEmail(row.Persona.Email)
+	if err := validateStruct(&row.Persona); err != nil {
+		return err
+	}
+
+	if line, ok := emails[row.Persona.Email]; ok {
+		return NewConflictError("email_duplicated_in_import", fmt.Sprintf("el email se repite en la fila %d", line))
+	}
+	emails[row.Persona.Email] = row.Line
+	return nil
+}
+
+// checkImportConflicts verifica contra la base las filas válidas con dos consultas en total, una
+// para los emails ya registrados y otra para las áreas vigentes, en lugar de dos por fila. Las
+// filas que fallan se marcan en el reporte y se quitan de personas.
+func (s *personaService) checkImportConflicts(ctx context.Context, report *model.ImportReport, personas []*model.Persona) (err error) {
+	ctx, span := startSpan(ctx, "personaService.checkImportConflicts")
+	defer func() { endSpan(span, err) }()
+
+	var emails []string
+	areaIDs := map[uint]bool{}
+	for _, persona := range personas {
+		if persona != nil {
+			emails = append(emails, persona.Email)
+			areaIDs[persona.AreaID] = true
+		}
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+
+	existing, err := s.repo.GetExistingEmails(ctx, emails)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, email := range existing {
+		taken[email] = true
+	}
+
+	ids := make([]uint, 0, len(areaIDs))
+	for id := range areaIDs {
+		ids = append(ids, id)
+	}
+	live, err := s.areaRepo.GetExistingIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	liveAreas := make(map[uint]bool, len(live))
+	for _, id := range live {
+		liveAreas[id] = true
+	}
+
+	for i, persona := range personas {
+		var rowErr *Error
+		switch {
+		case persona == nil:
+			continue
+		case taken[persona.Email]:
+			rowErr = errEmailTaken()
+		case !liveAreas[persona.AreaID]:
+			rowErr = errInvalidArea()
+		default:
+			continue
+		}
+		setRowError(&report.Rows[i], rowErr)
+		personas[i] = nil
+	}
+	return nil
+}
+
+// importAtomic crea todas las personas en una sola transacción si ninguna fila tiene errores
+func (s *personaService) importAtomic(ctx context.Context, report *model.ImportReport, personas []*model.Persona) error {
+	if report.Valid != report.Total {
+		for i := range report.Rows {
+			if report.Rows[i].Status == model.ImportRowValid {
+				report.Rows[i].Status = model.ImportRowSkipped
+			}
+		}
+		return nil
+	}
+
+	batch := make([]model.Persona, len(personas))
+	entries := make([]model.AuditEntry, len(personas))
+	for i, persona := range personas {
+		batch[i] = *persona
+		entries[i] = *newAuditEntry(ctx, model.AuditEntityPersona, 0, model.AuditCreate, nil, persona.AuditFields())
+	}
+
+	if err := s.repo.CreateBatch(ctx, batch, entries); err != nil {
+		return translateError(err, nil, NewConflictError("email_taken", "uno de los correos electrónicos ya fue registrado durante la importación; no se creó ninguna persona"))
+	}
+
+	for i := range batch {
+		report.Rows[i].Status = model.ImportRowCreated
+		report.Rows[i].ID = batch[i].ID
+	}
+	report.Created = len(batch)
+	return nil
+}
+
+// importBestEffort crea una a una las personas válidas y registra en el reporte las que fallan
+func (s *personaService) importBestEffort(ctx context.Context, report *model.ImportReport, personas []*model.Persona) error {
+	for i, persona := range personas {
+		if persona == nil {
+			continue
+		}
+
+		entry := newAuditEntry(ctx, model.AuditEntityPersona, 0, model.AuditCreate, nil, persona.AuditFields())
+		err := translateError(s.repo.Create(ctx, persona, entry), nil, errEmailTaken())
+		if err != nil {
+			var domainErr *Error
+			if !errors.As(err, &domainErr) {
+				return err
+			}
+			report.Valid--
+			report.Failed++
+			setRowError(&report.Rows[i], domainErr)
+			continue
+		}
+
+		report.Rows[i].Status = model.ImportRowCreated
+		report.Rows[i].ID = persona.ID
+		report.Created++
+	}
+	return nil
+}
+
+// setRowError marca la fila como fallida con el código y mensaje del error
+func setRowError(result *model.ImportRowResult, err *Error) {
+	result.Status = model.ImportRowFailed
+	result.Code = err.Code
+	result.Field = err.Field
+	result.Error = err.Message
+}
@@ -3,82 +3,243 @@ package service
 import (
 	"backend/internal/model"
 	"backend/internal/repository"
+	"context"
 	"errors"
 	"gorm.io/gorm"
 )
 
 type PersonaService interface {
-	Create(persona *model.Persona) error
-	GetAll() ([]model.Persona, error)
-	GetByID(id uint) (*model.Persona, error)
-	GetByEmail(email string) (*model.Persona, error)
-	Update(id uint, persona *model.Persona) error
-	Delete(id uint) error
+	Create(ctx context.Context, persona *model.Persona) error
+	GetAll(ctx context.Context, query model.PersonaQuery) ([]model.Persona, int64, error)
+	Export(ctx context.Context, query model.PersonaQuery, fn func(row *model.PersonaExport) error) error
+	GetByID(ctx context.Context, id uint) (*model.Persona, error)
+	GetByEmail(ctx context.Context, email string) (*model.Persona, error)
+	Update(ctx context.Context, id uint, persona *model.Persona) error
+	Delete(ctx context.Context, id, version uint) error
+	Purge(ctx context.Context, id, version uint) error
+	Restore(ctx context.Context, id uint) (*model.Persona, error)
+	Import(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions) (*model.ImportReport, error)
 }
 
 type personaService struct {
-	repo repository.PersonaRepository
+	repo     repository.PersonaRepository
+	areaRepo repository.AreaRepository
 }
 
-func NewPersonaService(repo repository.PersonaRepository) PersonaService {
-	return &personaService{repo: repo}
+func NewPersonaService(repo repository.PersonaRepository, areaRepo repository.AreaRepository) PersonaService {
+	return &personaService{repo: repo, areaRepo: areaRepo}
 }
 
-func (s *personaService) Create(persona *model.Persona) error {
+// Create registra la persona con el email normalizado. La verificación previa del email da un
+// error claro en el caso habitual; si otra petición registra el mismo email al mismo tiempo,
+// el índice único de la base rechaza la segunda y se retorna el mismo error de conflicto.
+func (s *personaService) Create(ctx context.Context, persona *model.Persona) (err error) {
+	ctx, span := startSpan(ctx, "personaService.Create")
+	defer func() { endSpan(span, err) }()
+
+	persona.Email = model.NormalizeEmail(persona.Email)
+
 	// Validar que el email no exista
-	existingPersona, err := s.repo.GetByEmail(persona.Email)
-	if err == nil && existingPersona.ID != 0 {
-		return errors.New("el correo electrónico ya está registrado")
+	if err := s.checkEmailAvailable(ctx, persona.Email, 0); err != nil {
+		return err
+	}
+
+	// Validar que el área existe
+	if err := s.checkAreaExists(ctx, persona.AreaID); err != nil {
+		return err
 	}
-	
-	// Validar que el área existe (si se necesita, descomentar)
-	// if persona.AreaID == 0 {
-	//     return errors.New("debe proporcionar un área válida")
-	// }
-	
-	return s.repo.Create(persona)
+
+	entry := newAuditEntry(ctx, model.AuditEntityPersona, 0, model.AuditCreate, nil, persona.AuditFields())
+	return translateError(s.repo.Create(ctx, persona, entry), nil, errEmailTaken())
 }
 
-func (s *personaService) GetAll() ([]model.Persona, error) {
-	return s.repo.GetAll()
+func (s *personaService) GetAll(ctx context.Context, query model.PersonaQuery) ([]model.Persona, int64, error) {
+	query.Normalize()
+	return s.repo.GetAll(ctx, query)
 }
 
-func (s *personaService) GetByID(id uint) (*model.Persona, error) {
-	return s.repo.GetByID(id)
+// Export recorre todas las personas que cumplen los filtros, sin paginar, en el orden pedido
+func (s *personaService) Export(ctx context.Context, query model.PersonaQuery, fn func(row *model.PersonaExport) error) error {
+	return s.repo.Export(ctx, query, fn)
 }
 
-func (s *personaService) GetByEmail(email string) (*model.Persona, error) {
-	return s.repo.GetByEmail(email)
+func (s *personaService) GetByID(ctx context.Context, id uint) (*model.Persona, error) {
+	persona, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err, errPersonaNotFound(), nil)
+	}
+	return persona, nil
 }
 
-func (s *personaService) Update(id uint, persona *model.Persona) error {
-	existingPersona, err := s.repo.GetByID(id)
+// GetByEmail busca una persona vigente por email, sin distinguir mayúsculas
+func (s *personaService) GetByEmail(ctx context.Context, email string) (*model.Persona, error) {
+	persona, err := s.repo.GetByEmail(ctx, model.NormalizeEmail(email))
+	if err != nil {
+		return nil, translateError(err, errPersonaNotFound(), nil)
+	}
+	return persona, nil
+}
+
+// Update reemplaza los campos editables de la persona y deja en persona el registro actualizado.
+// Los campos de gorm.Model se conservan del registro existente. Si persona.Version no es
+// cero, la actualización solo se aplica sobre esa versión.
+func (s *personaService) Update(ctx context.Context, id uint, persona *model.Persona) (err error) {
+	ctx, span := startSpan(ctx, "personaService.Update")
+	defer func() { endSpan(span, err) }()
+
+	persona.Email = model.NormalizeEmail(persona.Email)
+
+	existingPersona, err := s.GetByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("persona no encontrada")
-		}
 		return err
 	}
 
+	if persona.Version != 0 && persona.Version != existingPersona.Version {
+		return errVersionMismatch()
+	}
+
 	// Validar que el email no esté en uso por otra persona
 	if persona.Email != existingPersona.Email {
-		emailPersona, err := s.repo.GetByEmail(persona.Email)
-		if err == nil && emailPersona.ID != id {
-			return errors.New("el correo electrónico ya está registrado")
+		if err := s.checkEmailAvailable(ctx, persona.Email, id); err != nil {
+			return err
 		}
 	}
 
-	persona.ID = id
-	return s.repo.Update(persona)
+	// Validar que el área existe
+	if err := s.checkAreaExists(ctx, persona.AreaID); err != nil {
+		return err
+	}
+
+	before := existingPersona.AuditFields()
+	existingPersona.Nombre = persona.Nombre
+	existingPersona.Email = persona.Email
+	if existingPersona.AreaID != persona.AreaID {
+		existingPersona.AreaID = persona.AreaID
+		existingPersona.Area = nil
+	}
+	entry := newAuditEntry(ctx, model.AuditEntityPersona, id, model.AuditUpdate, before, existingPersona.AuditFields())
+
+	if err := translateError(s.repo.Update(ctx, existingPersona, entry), nil, errEmailTaken()); err != nil {
+		return err
+	}
+
+	*persona = *existingPersona
+	return nil
+}
+
+// Delete elimina una persona. Si version no es cero, solo se elimina si conserva esa versión.
+func (s *personaService) Delete(ctx context.Context, id, version uint) (err error) {
+	ctx, span := startSpan(ctx, "personaService.Delete")
+	defer func() { endSpan(span, err) }()
+
+	existingPersona, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if version != 0 && version != existingPersona.Version {
+		return errVersionMismatch()
+	}
+
+	entry := newAuditEntry(ctx, model.AuditEntityPersona, id, model.AuditDelete, existingPersona.AuditFields(), nil)
+	return translateError(s.repo.Delete(ctx, id, version, entry), errPersonaNotFound(), nil)
 }
 
-func (s *personaService) Delete(id uint) error {
-	_, err := s.repo.GetByID(id)
+// Purge elimina definitivamente una persona, esté vigente o eliminada.
+// Si version no es cero, solo se elimina si conserva esa versión.
+func (s *personaService) Purge(ctx context.Context, id, version uint) (err error) {
+	ctx, span := startSpan(ctx, "personaService.Purge")
+	defer func() { endSpan(span, err) }()
+
+	existingPersona, err := s.repo.GetByIDWithDeleted(ctx, id)
 	if err != nil {
+		return translateError(err, errPersonaNotFound(), nil)
+	}
+
+	if version != 0 && version != existingPersona.Version {
+		return errVersionMismatch()
+	}
+
+	entry := newAuditEntry(ctx, model.AuditEntityPersona, id, model.AuditPurge, existingPersona.AuditFields(), nil)
+	return translateError(s.repo.Purge(ctx, id, version, entry), errPersonaNotFound(), nil)
+}
+
+// Restore revierte la eliminación de una persona y retorna el registro restaurado.
+// El área de la persona debe seguir vigente y su email no debe estar en uso.
+func (s *personaService) Restore(ctx context.Context, id uint) (_ *model.Persona, err error) {
+	ctx, span := startSpan(ctx, "personaService.Restore")
+	defer func() { endSpan(span, err) }()
+
+	deletedPersona, err := s.repo.GetByIDWithDeleted(ctx, id)
+	if err != nil {
+		return nil, translateError(err, errPersonaNotFound(), nil)
+	}
+
+	if !deletedPersona.DeletedAt.Valid {
+		return nil, errNotDeleted("la persona no está eliminada")
+	}
+
+	if _, err := s.areaRepo.GetByID(ctx, deletedPersona.AreaID); err != nil {
+		return nil, translateError(err, NewConflictError("area_deleted", "el área de la persona está eliminada; restáurela o elimine la persona definitivamente"), nil)
+	}
+
+	if err := s.checkEmailAvailable(ctx, deletedPersona.Email, id); err != nil {
+		return nil, err
+	}
+
+	entry := newAuditEntry(ctx, model.AuditEntityPersona, id, model.AuditRestore, nil, deletedPersona.AuditFields())
+	if err := s.repo.Restore(ctx, id, entry); err != nil {
+		return nil, translateError(err, errNotDeleted("la persona no está eliminada"), errEmailTaken())
+	}
+	return s.GetByID(ctx, id)
+}
+
+// checkEmailAvailable verifica que el email no pertenezca a otra persona distinta de exceptID
+func (s *personaService) checkEmailAvailable(ctx context.Context, email string, exceptID uint) (err error) {
+	ctx, span := startSpan(ctx, "personaService.checkEmailAvailable")
+	defer func() { endSpan(span, err) }()
+
+	existingPersona, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existingPersona.ID != 0 && existingPersona.ID != exceptID {
+		return errEmailTaken()
+	}
+	return nil
+}
+
+// checkAreaExists verifica que el área exista y no esté eliminada
+func (s *personaService) checkAreaExists(ctx context.Context, areaID uint) (err error) {
+	ctx, span := startSpan(ctx, "personaService.checkAreaExists")
+	defer func() { endSpan(span, err) }()
+
+	if areaID == 0 {
+		return errInvalidArea()
+	}
+	if _, err := s.areaRepo.GetByID(ctx, areaID); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("persona no encontrada")
+			return errInvalidArea()
 		}
 		return err
 	}
-	return s.repo.Delete(id)
+	return nil
+}
+
+// errPersonaNotFound es el error del dominio para una persona inexistente
+func errPersonaNotFound() *Error {
+	return NewNotFoundError("persona_not_found", "persona no encontrada")
+}
+
+// errEmailTaken es el error del dominio para un email que ya pertenece a otra persona
+func errEmailTaken() *Error {
+	return NewConflictError("email_taken", "el correo electrónico ya está registrado")
+}
+
+// errInvalidArea es el error de validación para un area_id que no corresponde a un área vigente
+func errInvalidArea() *Error {
+	return NewValidationError("invalid_area", "area_id", "el área indicada no existe")
 }
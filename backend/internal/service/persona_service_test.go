@@ -2,17 +2,28 @@ package service
 
 import (
 	"backend/internal/model"
+	"context"
 	"errors"
+	"slices"
 	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
 )
 
 // Mock del repositorio de personas
 type mockPersonaRepository struct {
 	personas   []model.Persona
 	shouldFail bool
+	lastQuery  model.PersonaQuery
+	audit      []*model.AuditEntry
 }
 
-func (m *mockPersonaRepository) Create(persona *model.Persona) error {
+func (m *mockPersonaRepository) Create(ctx context.Context, persona *model.Persona, entry *model.AuditEntry) error {
 	if m.shouldFail {
 		return errors.New("database error")
 	}
@@ -26,56 +37,162 @@ func (m *mockPersonaRepository) Create(persona *model.Persona) error {
 	
 	persona.ID = uint(len(m.personas) + 1)
 	m.personas = append(m.personas, *persona)
+	entry.EntityID = persona.ID
+	m.audit = append(m.audit, entry)
 	return nil
 }
 
-func (m *mockPersonaRepository) GetAll() ([]model.Persona, error) {
+func (m *mockPersonaRepository) CreateBatch(ctx context.Context, personas []model.Persona, entries []model.AuditEntry) error {
 	if m.shouldFail {
-		return nil, errors.New("database error")
+		return errors.New("database error")
 	}
-	return m.personas, nil
+
+	for i := range personas {
+		personas[i].ID = uint(len(m.personas) + 1)
+		m.personas = append(m.personas, personas[i])
+		entries[i].EntityID = personas[i].ID
+		m.audit = append(m.audit, &entries[i])
+	}
+	return nil
 }
 
-func (m *mockPersonaRepository) GetByID(id uint) (*model.Persona, error) {
+func (m *mockPersonaRepository) GetAll(ctx context.Context, query model.PersonaQuery) ([]model.Persona, int64, error) {
+	m.lastQuery = query
+	if m.shouldFail {
+		return nil, 0, errors.New("database error")
+	}
+	return m.personas, int64(len(m.personas)), nil
+}
+
+func (m *mockPersonaRepository) Export(ctx context.Context, query model.PersonaQuery, fn func(row *model.PersonaExport) error) error {
+	m.lastQuery = query
+	if m.shouldFail {
+		return errors.New("database error")
+	}
+
+	for _, persona := range m.personas {
+		row := model.PersonaExport{ID: persona.ID, Nombre: persona.Nombre, Email: persona.Email, AreaID: persona.AreaID}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockPersonaRepository) GetByID(ctx context.Context, id uint) (*model.Persona, error) {
 	if m.shouldFail {
 		return nil, errors.New("database error")
 	}
 	
+	for _, persona := range m.personas {
+		if persona.ID == id && !persona.DeletedAt.Valid {
+			return &persona, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockPersonaRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*model.Persona, error) {
+	if m.shouldFail {
+		return nil, errors.New("database error")
+	}
+
 	for _, persona := range m.personas {
 		if persona.ID == id {
 			return &persona, nil
 		}
 	}
-	return nil, errors.New("persona not found")
+	return nil, gorm.ErrRecordNotFound
 }
 
-func (m *mockPersonaRepository) GetByEmail(email string) (*model.Persona, error) {
+func (m *mockPersonaRepository) GetByEmail(ctx context.Context, email string) (*model.Persona, error) {
 	if m.shouldFail {
 		return nil, errors.New("database error")
 	}
 	
 	for _, persona := range m.personas {
-		if persona.Email == email {
+		if persona.Email == email && !persona.DeletedAt.Valid {
 			return &persona, nil
 		}
 	}
-	return nil, errors.New("persona not found")
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockPersonaRepository) GetExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	if m.shouldFail {
+		return nil, errors.New("database error")
+	}
+
+	var existing []string
+	for _, persona := range m.personas {
+		if !persona.DeletedAt.Valid && slices.Contains(emails, model.NormalizeEmail(persona.Email)) {
+			existing = append(existing, model.NormalizeEmail(persona.Email))
+		}
+	}
+	return existing, nil
+}
+
+func (m *mockPersonaRepository) GetByArea(ctx context.Context, areaID uint) ([]model.Persona, error) {
+	if m.shouldFail {
+		return nil, errors.New("database error")
+	}
+
+	var personas []model.Persona
+	for _, persona := range m.personas {
+		if persona.AreaID == areaID {
+			personas = append(personas, persona)
+		}
+	}
+	return personas, nil
 }
 
-func (m *mockPersonaRepository) Update(persona *model.Persona) error {
+func (m *mockPersonaRepository) Update(ctx context.Context, persona *model.Persona, entry *model.AuditEntry) error {
 	if m.shouldFail {
 		return errors.New("database error")
 	}
+	m.audit = append(m.audit, entry)
 	return nil
 }
 
-func (m *mockPersonaRepository) Delete(id uint) error {
+func (m *mockPersonaRepository) Delete(ctx context.Context, id, version uint, entry *model.AuditEntry) error {
 	if m.shouldFail {
 		return errors.New("database error")
 	}
+	m.audit = append(m.audit, entry)
+	return nil
+}
+
+func (m *mockPersonaRepository) Purge(ctx context.Context, id, version uint, entry *model.AuditEntry) error {
+	if m.shouldFail {
+		return errors.New("database error")
+	}
+	return nil
+}
+
+func (m *mockPersonaRepository) Restore(ctx context.Context, id uint, entry *model.AuditEntry) error {
+	if m.shouldFail {
+		return errors.New("database error")
+	}
+
+	for i := range m.personas {
+		if m.personas[i].ID == id {
+			m.personas[i].DeletedAt = gorm.DeletedAt{}
+		}
+	}
 	return nil
 }
 
+// newMockAreaRepository crea un repositorio de áreas con las áreas 1 y 2
+func newMockAreaRepository() *mockAreaRepository {
+	ventas := model.Area{Nombre: "Ventas"}
+	ventas.ID = 1
+
+	marketing := model.Area{Nombre: "Marketing"}
+	marketing.ID = 2
+
+	return &mockAreaRepository{areas: []model.Area{ventas, marketing}}
+}
+
 // TestGetAllPersonas prueba la obtención de todas las personas
 func TestGetAllPersonas(t *testing.T) {
 	// Arrange
@@ -98,10 +215,10 @@ func TestGetAllPersonas(t *testing.T) {
 		shouldFail: false,
 	}
 	
-	service := NewPersonaService(mockRepo)
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
 
 	// Act
-	personas, err := service.GetAll()
+	personas, _, err := service.GetAll(context.Background(), model.PersonaQuery{})
 
 	// Assert
 	if err != nil {
@@ -129,7 +246,7 @@ func TestCreatePersona(t *testing.T) {
 		shouldFail: false,
 	}
 	
-	service := NewPersonaService(mockRepo)
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
 	
 	newPersona := &model.Persona{
 		Nombre:   "Carlos Ruiz",
@@ -138,7 +255,7 @@ func TestCreatePersona(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(newPersona)
+	err := service.Create(context.Background(), newPersona)
 
 	// Assert
 	if err != nil {
@@ -169,7 +286,7 @@ func TestCreatePersonaDuplicateEmail(t *testing.T) {
 		shouldFail: false,
 	}
 	
-	service := NewPersonaService(mockRepo)
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
 	
 	duplicatePersona := &model.Persona{
 		Nombre:   "Otra Ana",
@@ -178,7 +295,7 @@ func TestCreatePersonaDuplicateEmail(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(duplicatePersona)
+	err := service.Create(context.Background(), duplicatePersona)
 
 	// Assert
 	if err == nil {
@@ -190,6 +307,30 @@ func TestCreatePersonaDuplicateEmail(t *testing.T) {
 	}
 }
 
+// TestCreatePersonaNormalizesEmail prueba que el email se guarde sin espacios y en minúsculas
+// y que un email que solo difiere en mayúsculas se considere repetido
+func TestCreatePersonaNormalizesEmail(t *testing.T) {
+	// Arrange
+	mockRepo := &mockPersonaRepository{}
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+	persona := &model.Persona{Nombre: "Juan", Email: "  Juan@X.com ", AreaID: 1}
+
+	// Act
+	err := service.Create(context.Background(), persona)
+	duplicateErr := service.Create(context.Background(), &model.Persona{Nombre: "Otro Juan", Email: "JUAN@x.COM", AreaID: 1})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+	if persona.Email != "juan@x.com" || mockRepo.personas[0].Email != "juan@x.com" {
+		t.Errorf("Se esperaba el email normalizado 'juan@x.com', pero se obtuvo: %q", persona.Email)
+	}
+	if !errors.Is(duplicateErr, ErrConflict) {
+		t.Errorf("Se esperaba un conflicto por email repetido, pero se obtuvo: %v", duplicateErr)
+	}
+}
+
 // TestGetAllPersonasError prueba el manejo de errores
 func TestGetAllPersonasError(t *testing.T) {
 	// Arrange
@@ -197,10 +338,10 @@ func TestGetAllPersonasError(t *testing.T) {
 		shouldFail: true,
 	}
 	
-	service := NewPersonaService(mockRepo)
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
 
 	// Act
-	personas, err := service.GetAll()
+	personas, _, err := service.GetAll(context.Background(), model.PersonaQuery{})
 
 	// Assert
 	if err == nil {
@@ -220,10 +361,10 @@ func TestGetAllPersonasEmpty(t *testing.T) {
 		shouldFail: false,
 	}
 	
-	service := NewPersonaService(mockRepo)
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
 
 	// Act
-	personas, err := service.GetAll()
+	personas, _, err := service.GetAll(context.Background(), model.PersonaQuery{})
 
 	// Assert
 	if err != nil {
@@ -234,3 +375,448 @@ func TestGetAllPersonasEmpty(t *testing.T) {
 		t.Errorf("Se esperaba lista vacía, pero se obtuvieron: %d personas", len(personas))
 	}
 }
+
+// TestGetAllPersonasPaginationDefaults prueba que se apliquen los valores por defecto de paginación
+func TestGetAllPersonasPaginationDefaults(t *testing.T) {
+	// Arrange
+	mockRepo := &mockPersonaRepository{
+		personas:   []model.Persona{},
+		shouldFail: false,
+	}
+
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	// Act
+	_, _, err := service.GetAll(context.Background(), model.PersonaQuery{
+		ListOptions: model.ListOptions{Page: 0, PageSize: 1000},
+	})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if mockRepo.lastQuery.Page != 1 {
+		t.Errorf("Se esperaba página 1, pero se obtuvo: %d", mockRepo.lastQuery.Page)
+	}
+
+	if mockRepo.lastQuery.PageSize != model.MaxPageSize {
+		t.Errorf("Se esperaba tamaño de página %d, pero se obtuvo: %d", model.MaxPageSize, mockRepo.lastQuery.PageSize)
+	}
+}
+
+// TestPersonaServiceDomainErrors prueba que los errores del servicio tengan la categoría correcta
+func TestPersonaServiceDomainErrors(t *testing.T) {
+	// Arrange
+	existingPersona := model.Persona{
+		Nombre: "Ana García",
+		Email:  "ana@test.com",
+		AreaID: 1,
+	}
+	existingPersona.ID = 1
+
+	mockRepo := &mockPersonaRepository{
+		personas:   []model.Persona{existingPersona},
+		shouldFail: false,
+	}
+
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	// Act
+	_, notFoundErr := service.GetByID(context.Background(), 99)
+	conflictErr := service.Create(context.Background(), &model.Persona{Nombre: "Otra Ana", Email: "ana@test.com", AreaID: 1})
+
+	// Assert
+	if !errors.Is(notFoundErr, ErrNotFound) {
+		t.Errorf("Se esperaba ErrNotFound, pero se obtuvo: %v", notFoundErr)
+	}
+
+	if !errors.Is(conflictErr, ErrConflict) {
+		t.Errorf("Se esperaba ErrConflict, pero se obtuvo: %v", conflictErr)
+	}
+
+	var domainErr *Error
+	if !errors.As(conflictErr, &domainErr) || domainErr.Code != "email_taken" {
+		t.Errorf("Se esperaba el código 'email_taken', pero se obtuvo: %v", domainErr)
+	}
+}
+
+// TestCreatePersonaInvalidArea prueba que no se permita crear una persona en un área inexistente
+func TestCreatePersonaInvalidArea(t *testing.T) {
+	// Arrange
+	mockRepo := &mockPersonaRepository{
+		personas:   []model.Persona{},
+		shouldFail: false,
+	}
+
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	newPersona := &model.Persona{
+		Nombre: "Carlos Ruiz",
+		Email:  "carlos@test.com",
+		AreaID: 99,
+	}
+
+	// Act
+	err := service.Create(context.Background(), newPersona)
+
+	// Assert
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Se esperaba ErrValidation, pero se obtuvo: %v", err)
+	}
+
+	var domainErr *Error
+	if !errors.As(err, &domainErr) || domainErr.Field != "area_id" {
+		t.Errorf("Se esperaba un error sobre el campo 'area_id', pero se obtuvo: %v", domainErr)
+	}
+
+	if len(mockRepo.personas) != 0 {
+		t.Errorf("No se esperaba crear la persona, pero hay: %d", len(mockRepo.personas))
+	}
+}
+
+// TestUpdatePersonaInvalidArea prueba que no se permita mover una persona a un área inexistente
+func TestUpdatePersonaInvalidArea(t *testing.T) {
+	// Arrange
+	existingPersona := model.Persona{
+		Nombre: "Ana García",
+		Email:  "ana@test.com",
+		AreaID: 1,
+	}
+	existingPersona.ID = 1
+
+	mockRepo := &mockPersonaRepository{
+		personas:   []model.Persona{existingPersona},
+		shouldFail: false,
+	}
+
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	// Act
+	err := service.Update(context.Background(), 1, &model.Persona{Nombre: "Ana García", Email: "ana@test.com", AreaID: 7})
+
+	// Assert
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Se esperaba ErrValidation, pero se obtuvo: %v", err)
+	}
+}
+
+// TestUpdatePersonaStaleVersion prueba que no se actualice una persona con una versión desactualizada
+func TestUpdatePersonaStaleVersion(t *testing.T) {
+	// Arrange
+	existingPersona := model.Persona{
+		Nombre:  "Ana García",
+		Email:   "ana@test.com",
+		AreaID:  1,
+		Version: 4,
+	}
+	existingPersona.ID = 1
+
+	mockRepo := &mockPersonaRepository{
+		personas:   []model.Persona{existingPersona},
+		shouldFail: false,
+	}
+
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	// Act
+	err := service.Update(context.Background(), 1, &model.Persona{Nombre: "Ana G.", Email: "ana@test.com", AreaID: 1, Version: 3})
+
+	// Assert
+	if !errors.Is(err, ErrPrecondition) {
+		t.Errorf("Se esperaba ErrPrecondition, pero se obtuvo: %v", err)
+	}
+}
+
+// TestRestorePersona prueba la restauración de una persona eliminada
+func TestRestorePersona(t *testing.T) {
+	// Arrange
+	deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	deletedPersona := model.Persona{Nombre: "Ana García", Email: "ana@test.com", AreaID: 1}
+	deletedPersona.ID = 1
+	deletedPersona.DeletedAt = deletedAt
+
+	reusedEmail := model.Persona{Nombre: "Luis Díaz", Email: "luis@test.com", AreaID: 1}
+	reusedEmail.ID = 2
+	reusedEmail.DeletedAt = deletedAt
+
+	livePersona := model.Persona{Nombre: "Luis Díaz", Email: "luis@test.com", AreaID: 2}
+	livePersona.ID = 3
+
+	mockRepo := &mockPersonaRepository{
+		personas:   []model.Persona{deletedPersona, reusedEmail, livePersona},
+		shouldFail: false,
+	}
+
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	// Act
+	restored, err := service.Restore(context.Background(), 1)
+	_, conflictErr := service.Restore(context.Background(), 2)
+	_, notDeletedErr := service.Restore(context.Background(), 3)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if restored == nil || restored.DeletedAt.Valid {
+		t.Errorf("Se esperaba la persona restaurada, pero se obtuvo: %+v", restored)
+	}
+
+	if !errors.Is(conflictErr, ErrConflict) {
+		t.Errorf("Se esperaba ErrConflict por email en uso, pero se obtuvo: %v", conflictErr)
+	}
+
+	if !errors.Is(notDeletedErr, ErrConflict) {
+		t.Errorf("Se esperaba ErrConflict por persona vigente, pero se obtuvo: %v", notDeletedErr)
+	}
+}
+
+// importRows arma filas de importación con una fila inválida en la línea 3
+func importRows() []model.ImportRow {
+	return []model.ImportRow{
+		{Line: 2, Persona: model.Persona{Nombre: "Ana García", Email: "ana@test.com", AreaID: 1}},
+		{Line: 3, Persona: model.Persona{Nombre: "Luis Díaz", Email: "no-es-email"}, AreaNombre: "ventas"},
+		{Line: 4, Persona: model.Persona{Nombre: "Eva Ruiz", Email: "eva@test.com"}, AreaNombre: "Marketing"},
+	}
+}
+
+// TestImportPersonasAtomic prueba que el modo atómico no crea ninguna persona si una fila es inválida
+func TestImportPersonasAtomic(t *testing.T) {
+	// Arrange
+	mockRepo := &mockPersonaRepository{
+		personas:   []model.Persona{},
+		shouldFail: false,
+	}
+
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	// Act
+	report, err := service.Import(context.Background(), importRows(), model.ImportOptions{Mode: model.ImportAtomic})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if len(mockRepo.personas) != 0 || report.Created != 0 {
+		t.Errorf("Se esperaba que no se creara ninguna persona, pero se crearon: %d", len(mockRepo.personas))
+	}
+
+	if report.Failed != 1 || report.Rows[1].Status != model.ImportRowFailed || report.Rows[1].Field != "email" {
+		t.Errorf("Se esperaba un error en el campo email de la línea 3, pero se obtuvo: %+v", report.Rows[1])
+	}
+
+	if report.Rows[0].Status != model.ImportRowSkipped {
+		t.Errorf("Se esperaba la fila válida omitida, pero se obtuvo: %s", report.Rows[0].Status)
+	}
+}
+
+// TestImportPersonasBestEffort prueba que el modo best effort crea las filas válidas y reporta las demás
+func TestImportPersonasBestEffort(t *testing.T) {
+	// Arrange
+	mockRepo := &mockPersonaRepository{
+		personas:   []model.Persona{},
+		shouldFail: false,
+	}
+
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	rows := append(importRows(), model.ImportRow{Line: 5, Persona: model.Persona{Nombre: "Ana Gómez", Email: "ana@test.com", AreaID: 2}})
+
+	// Act
+	report, err := service.Import(context.Background(), rows, model.ImportOptions{Mode: model.ImportBestEffort})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if report.Created != 2 || len(mockRepo.personas) != 2 {
+		t.Errorf("Se esperaban 2 personas creadas, pero se obtuvo: %d", report.Created)
+	}
+
+	if mockRepo.personas[1].AreaID != 2 {
+		t.Errorf("Se esperaba el área resuelta por nombre con ID 2, pero se obtuvo: %d", mockRepo.personas[1].AreaID)
+	}
+
+	if report.Rows[3].Code != "email_duplicated_in_import" {
+		t.Errorf("Se esperaba el email repetido reportado, pero se obtuvo: %+v", report.Rows[3])
+	}
+}
+
+// TestImportPersonasDryRun prueba que el dry run solo valida las filas
+func TestImportPersonasDryRun(t *testing.T) {
+	// Arrange
+	mockRepo := &mockPersonaRepository{
+		personas:   []model.Persona{},
+		shouldFail: false,
+	}
+
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	rows := []model.ImportRow{
+		{Line: 2, Persona: model.Persona{Nombre: "Ana García", Email: "ana@test.com", AreaID: 1}},
+		{Line: 3, Persona: model.Persona{Nombre: "Luis Díaz", Email: "luis@test.com"}, AreaNombre: "Finanzas"},
+	}
+
+	// Act
+	report, err := service.Import(context.Background(), rows, model.ImportOptions{Mode: model.ImportAtomic, DryRun: true})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if len(mockRepo.personas) != 0 {
+		t.Errorf("Se esperaba que no se creara ninguna persona, pero se crearon: %d", len(mockRepo.personas))
+	}
+
+	if report.Rows[0].Status != model.ImportRowValid || report.Rows[1].Code != "invalid_area" {
+		t.Errorf("Se esperaba la primera fila válida y la segunda con área inválida, pero se obtuvo: %+v", report.Rows)
+	}
+}
+
+// TestImportPersonasExistingData prueba que se rechacen las filas con un email ya registrado,
+// sin distinguir mayúsculas, o con un área inexistente
+func TestImportPersonasExistingData(t *testing.T) {
+	// Arrange
+	existingPersona := model.Persona{Nombre: "Ana García", Email: "Ana@Test.com", AreaID: 1}
+	existingPersona.ID = 1
+	mockRepo := &mockPersonaRepository{personas: []model.Persona{existingPersona}}
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	rows := []model.ImportRow{
+		{Line: 2, Persona: model.Persona{Nombre: "Ana García", Email: "ana@test.com", AreaID: 1}},
+		{Line: 3, Persona: model.Persona{Nombre: "Luis Díaz", Email: "luis@test.com", AreaID: 9}},
+		{Line: 4, Persona: model.Persona{Nombre: "Eva Ruiz", Email: "eva@test.com", AreaID: 2}},
+	}
+
+	// Act
+	report, err := service.Import(context.Background(), rows, model.ImportOptions{Mode: model.ImportBestEffort, DryRun: true})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if report.Rows[0].Code != "email_taken" || report.Rows[1].Code != "invalid_area" || report.Rows[2].Status != model.ImportRowValid {
+		t.Errorf("Se esperaba el email registrado, el área inexistente y una fila válida, pero se obtuvo: %+v", report.Rows)
+	}
+
+	if report.Valid != 1 || report.Failed != 2 {
+		t.Errorf("Se esperaba 1 fila válida y 2 fallidas, pero se obtuvo: %d y %d", report.Valid, report.Failed)
+	}
+}
+
+// TestUpdatePersonaRecordsAudit prueba que el cambio de área quede auditado con su autor y los valores anteriores
+func TestUpdatePersonaRecordsAudit(t *testing.T) {
+	// Arrange
+	existingPersona := model.Persona{
+		Nombre: "Ana García",
+		Email:  "ana@test.com",
+		AreaID: 1,
+	}
+	existingPersona.ID = 1
+
+	mockRepo := &mockPersonaRepository{
+		personas:   []model.Persona{existingPersona},
+		shouldFail: false,
+	}
+
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+	ctx := model.ContextWithPrincipal(context.Background(), &model.Principal{UserID: 7, Username: "editora", Role: model.RoleHREditor})
+
+	// Act
+	err := service.Update(ctx, 1, &model.Persona{Nombre: "Ana García", Email: "ana@test.com", AreaID: 2})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	if len(mockRepo.audit) != 1 {
+		t.Fatalf("Se esperaba 1 entrada de auditoría, pero se obtuvo: %d", len(mockRepo.audit))
+	}
+
+	entry := mockRepo.audit[0]
+	if entry.Entity != model.AuditEntityPersona || entry.EntityID != 1 || entry.Action != model.AuditUpdate {
+		t.Errorf("Se esperaba la actualización de la persona 1, pero se obtuvo: %+v", entry)
+	}
+
+	if entry.Actor != "editora" || entry.UserID == nil || *entry.UserID != 7 {
+		t.Errorf("Se esperaba el autor editora (7), pero se obtuvo: %s %v", entry.Actor, entry.UserID)
+	}
+
+	expected := model.AuditChanges{"area_id": {Before: uint(1), After: uint(2)}}
+	if len(entry.Changes) != 1 || entry.Changes["area_id"] != expected["area_id"] {
+		t.Errorf("Se esperaba solo el cambio de area_id, pero se obtuvo: %+v", entry.Changes)
+	}
+}
+
+// TestCreatePersonaWithoutActor prueba que una operación sin usuario autenticado se audite como system
+func TestCreatePersonaWithoutActor(t *testing.T) {
+	// Arrange
+	mockRepo := &mockPersonaRepository{personas: []model.Persona{}}
+	service := NewPersonaService(mockRepo, newMockAreaRepository())
+
+	// Act
+	err := service.Create(context.Background(), &model.Persona{Nombre: "Luis Díaz", Email: "luis@test.com", AreaID: 1})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	entry := mockRepo.audit[0]
+	if entry.Actor != "system" || entry.Action != model.AuditCreate || entry.Changes["email"].After != "luis@test.com" || entry.Changes["email"].Before != nil {
+		t.Errorf("Se esperaba la creación auditada como system, pero se obtuvo: %+v", entry)
+	}
+}
+
+// TestCreatePersonaTracing prueba los spans de la creación y que un email repetido no marque el span como fallido
+func TestCreatePersonaTracing(t *testing.T) {
+	// Arrange
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	existingPersona := model.Persona{Nombre: "Ana García", Email: "ana@test.com", AreaID: 1}
+	existingPersona.ID = 1
+	service := NewPersonaService(&mockPersonaRepository{personas: []model.Persona{existingPersona}}, newMockAreaRepository())
+
+	// Act
+	err := service.Create(context.Background(), &model.Persona{Nombre: "Otra Ana", Email: "ana@test.com", AreaID: 1})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Se esperaba un error por email duplicado, pero se obtuvo nil")
+	}
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+
+	create, ok := spans["personaService.Create"]
+	if !ok {
+		t.Fatalf("Se esperaba el span personaService.Create, pero se obtuvo: %v", spans)
+	}
+
+	check, ok := spans["personaService.checkEmailAvailable"]
+	if !ok || check.Parent.SpanID() != create.SpanContext.SpanID() {
+		t.Errorf("Se esperaba el span del chequeo del email como hijo de la creación")
+	}
+
+	if create.Status.Code == codes.Error {
+		t.Errorf("Se esperaba que un error del dominio no marcara el span como fallido")
+	}
+
+	if len(create.Attributes) == 0 || create.Attributes[0].Value.AsString() != "email_taken" {
+		t.Errorf("Se esperaba el código del error en el span, pero se obtuvo: %v", create.Attributes)
+	}
+}
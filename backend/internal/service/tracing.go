@@ -0,0 +1,29 @@
+package service
+
+import (
+	"backend/internal/tracing"
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan abre el span de una operación del servicio, como "personaService.Create"
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name, attrs...)
+}
+
+// endSpan cierra el span de una operación. Los errores del dominio, como una validación o un
+// registro inexistente, quedan como atributo pero no marcan el span como fallido, porque no
+// son fallas del servidor.
+func endSpan(span trace.Span, err error) {
+	var domainErr *Error
+	switch {
+	case errors.As(err, &domainErr):
+		span.SetAttributes(attribute.String("error.code", domainErr.Code))
+	case err != nil:
+		tracing.RecordError(span, err)
+	}
+	span.End()
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type UserService interface {
+	Create(ctx context.Context, request model.UserRequest) (*model.User, error)
+}
+
+type userService struct {
+	users repository.UserRepository
+	areas repository.AreaRepository
+}
+
+func NewUserService(users repository.UserRepository, areas repository.AreaRepository) UserService {
+	return &userService{users: users, areas: areas}
+}
+
+// Create crea un usuario con el rol indicado. Un area-manager debe tener un área vigente,
+// que limita las personas que puede leer y editar; los demás roles no llevan área.
+func (s *userService) Create(ctx context.Context, request model.UserRequest) (*model.User, error) {
+	if !request.Role.Valid() {
+		return nil, NewValidationError("invalid_role", "role", fmt.Sprintf("el rol '%s' no existe", request.Role))
+	}
+
+	switch {
+	case request.Role == model.RoleAreaManager && request.AreaID == nil:
+		return nil, NewValidationError("area_required", "area_id", "el rol area-manager requiere un área")
+	case request.Role != model.RoleAreaManager && request.AreaID != nil:
+		return nil, NewValidationError("area_not_allowed", "area_id", fmt.Sprintf("el rol '%s' no se limita a un área", request.Role))
+	case request.AreaID != nil:
+		if _, err := s.areas.GetByID(ctx, *request.AreaID); err != nil {
+			return nil, translateError(err, errInvalidArea(), nil)
+		}
+	}
+
+	if _, err := s.users.GetByUsername(ctx, request.Username); err == nil {
+		return nil, errUsernameTaken()
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	hash, err := hashPassword(request.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{Username: request.Username, PasswordHash: hash, Role: request.Role, AreaID: request.AreaID}
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, translateError(err, nil, errUsernameTaken())
+	}
+	return user, nil
+}
+
+// errUsernameTaken es el error del dominio para un nombre de usuario repetido
+func errUsernameTaken() *Error {
+	return NewConflictError("username_taken", "el nombre de usuario ya está registrado")
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"backend/internal/authz"
+	"backend/internal/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCreateUser prueba las validaciones del rol y el área al crear un usuario
+func TestCreateUser(t *testing.T) {
+	areaID := uint(2)
+	missingAreaID := uint(9)
+
+	tests := []struct {
+		name         string
+		request      model.UserRequest
+		expectedErr  error
+		expectedCode string
+	}{
+		{
+			name:    "Viewer sin área",
+			request: model.UserRequest{Username: "lector", Password: "secreto123", Role: model.RoleViewer},
+		},
+		{
+			name:    "Area-manager con área",
+			request: model.UserRequest{Username: "jefa", Password: "secreto123", Role: model.RoleAreaManager, AreaID: &areaID},
+		},
+		{
+			name:         "Rol inexistente",
+			request:      model.UserRequest{Username: "otro", Password: "secreto123", Role: "root"},
+			expectedErr:  ErrValidation,
+			expectedCode: "invalid_role",
+		},
+		{
+			name:         "Area-manager sin área",
+			request:      model.UserRequest{Username: "otro", Password: "secreto123", Role: model.RoleAreaManager},
+			expectedErr:  ErrValidation,
+			expectedCode: "area_required",
+		},
+		{
+			name:         "Área para un rol sin alcance de área",
+			request:      model.UserRequest{Username: "otro", Password: "secreto123", Role: model.RoleHREditor, AreaID: &areaID},
+			expectedErr:  ErrValidation,
+			expectedCode: "area_not_allowed",
+		},
+		{
+			name:         "Área inexistente",
+			request:      model.UserRequest{Username: "otro", Password: "secreto123", Role: model.RoleAreaManager, AreaID: &missingAreaID},
+			expectedErr:  ErrValidation,
+			expectedCode: "invalid_area",
+		},
+		{
+			name:         "Contraseña corta",
+			request:      model.UserRequest{Username: "otro", Password: "corta", Role: model.RoleViewer},
+			expectedErr:  ErrValidation,
+			expectedCode: "password_too_short",
+		},
+		{
+			name:         "Usuario repetido",
+			request:      model.UserRequest{Username: "admin", Password: "secreto123", Role: model.RoleViewer},
+			expectedErr:  ErrConflict,
+			expectedCode: "username_taken",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			users := &mockUserRepository{users: []model.User{{Username: "admin", Role: model.RoleAdmin}}}
+			service := NewUserService(users, newMockAreaRepository())
+
+			// Act
+			user, err := service.Create(context.Background(), tt.request)
+
+			// Assert
+			if tt.expectedErr == nil {
+				if err != nil {
+					t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+				}
+				if user.ID == 0 || user.PasswordHash == tt.request.Password || len(users.users) != 2 {
+					t.Errorf("Se esperaba el usuario guardado con la contraseña en hash, pero se obtuvo: %+v", user)
+				}
+				return
+			}
+
+			var domainErr *Error
+			if !errors.Is(err, tt.expectedErr) || !errors.As(err, &domainErr) || domainErr.Code != tt.expectedCode {
+				t.Errorf("Se esperaba el error %s, pero se obtuvo: %v", tt.expectedCode, err)
+			}
+			if len(users.users) != 1 {
+				t.Errorf("Se esperaba que no se creara el usuario, pero hay: %d", len(users.users))
+			}
+		})
+	}
+}
+
+// TestCreateUserAreaManagerScope prueba que un area-manager creado con la API quede limitado a su área
+func TestCreateUserAreaManagerScope(t *testing.T) {
+	// Arrange
+	users := &mockUserRepository{}
+	userService := NewUserService(users, newMockAreaRepository())
+	authService := NewAuthService(users, &mockRefreshTokenRepository{}, AuthConfig{
+		Secret:     []byte("clave-de-prueba-de-32-caracteres!"),
+		Issuer:     "test",
+		AccessTTL:  time.Minute,
+		RefreshTTL: time.Hour,
+	})
+	areaID := uint(2)
+
+	// Act
+	_, err := userService.Create(context.Background(), model.UserRequest{Username: "jefa", Password: "secreto123", Role: model.RoleAreaManager, AreaID: &areaID})
+	if err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+	tokens, err := authService.Login(context.Background(), "jefa", "secreto123")
+	if err != nil {
+		t.Fatalf("Se esperaba iniciar sesión, pero se obtuvo: %v", err)
+	}
+	principal, err := authService.Authenticate(tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	if principal.Role != model.RoleAreaManager || principal.AreaID != 2 {
+		t.Errorf("Se esperaba un area-manager del área 2, pero se obtuvo: %+v", principal)
+	}
+	if decision := authz.DefaultPolicy.Decide(principal, authz.PersonasWrite); decision != (authz.Decision{Allowed: true, AreaID: 2}) {
+		t.Errorf("Se esperaba editar solo las personas del área 2, pero se obtuvo: %+v", decision)
+	}
+	if decision := authz.DefaultPolicy.Decide(principal, authz.UsersManage); decision.Allowed {
+		t.Errorf("Se esperaba que un area-manager no pudiera crear usuarios")
+	}
+}
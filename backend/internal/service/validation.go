@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate aplica las mismas reglas `binding` que Gin usa al recibir un modelo
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	// Los errores se reportan con el nombre JSON del campo
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// validateStruct valida las reglas binding del modelo y retorna la primera falla como error de validación
+func validateStruct(value interface{}) error {
+	err := validate.Struct(value)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) || len(fieldErrors) == 0 {
+		return err
+	}
+
+	fieldErr := fieldErrors[0]
+	return NewValidationError("invalid_field", fieldErr.Field(), fmt.Sprintf("el campo '%s' no cumple la regla '%s'", fieldErr.Field(), fieldErr.Tag()))
+}
@@ -0,0 +1,168 @@
+// Package testdb prepara una base PostgreSQL real para los tests de integración: la de
+// TEST_DATABASE_URL si está definida o una que se inicia en un contenedor con Testcontainers.
+// Sin Docker ni TEST_DATABASE_URL, o con -short, los tests que la usan se omiten. Solo debe
+// importarse desde archivos _test.go.
+package testdb
+
+import (
+	"backend/internal/migrate"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// lockID identifica el advisory lock con el que los paquetes que comparten la base de
+// TEST_DATABASE_URL se turnan para usarla; go test ./... los ejecuta en paralelo
+const lockID = 72_011_024
+
+var (
+	once       sync.Once
+	dsn        string
+	skipReason string
+	stop       = func() {}
+)
+
+// Main ejecuta los tests del paquete y al terminar libera la base. Se usa desde TestMain:
+//
+//	func TestMain(m *testing.M) { os.Exit(testdb.Main(m)) }
+func Main(m *testing.M) int {
+	code := m.Run()
+	stop()
+	return code
+}
+
+// Open retorna una conexión configurada como en el servidor a una base migrada y con las
+// tablas de negocio vacías. La base se prepara en el primer llamado; si no hay Postgres
+// disponible el test se omite.
+func Open(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(gormpostgres.Open(DSN(t)), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Error al conectar a la base de los tests: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.Exec("TRUNCATE personas, areas, audit_entries RESTART IDENTITY CASCADE").Error; err != nil {
+		t.Fatalf("Error al vaciar las tablas: %v", err)
+	}
+	return db
+}
+
+// DSN retorna la cadena de conexión a la base migrada de los tests, sin vaciar sus tablas
+func DSN(t *testing.T) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("los tests con Postgres se omiten con -short")
+	}
+	once.Do(start)
+	if skipReason != "" {
+		t.Skip(skipReason)
+	}
+	return dsn
+}
+
+// start prepara la base migrada y deja en stop la función que la libera
+func start() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var (
+		cleanup func()
+		err     error
+	)
+	if dsn = os.Getenv("TEST_DATABASE_URL"); dsn != "" {
+		cleanup, err = lock(dsn)
+	} else {
+		dsn, cleanup, err = runContainer(ctx)
+	}
+	if err != nil {
+		skipReason = fmt.Sprintf("no se pudo preparar Postgres; defina TEST_DATABASE_URL o inicie Docker: %v", err)
+		return
+	}
+	stop = cleanup
+
+	if err := migrateUp(ctx, dsn); err != nil {
+		skipReason = fmt.Sprintf("no se pudo migrar la base de los tests: %v", err)
+	}
+}
+
+// lock espera a que ningún otro paquete use la base de TEST_DATABASE_URL y la reserva hasta
+// que se llame a la función retornada
+func lock(dsn string) (func(), error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	// El lock es de la sesión: se libera al cerrar la conexión
+	if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		conn.Close()
+		db.Close()
+		return nil, err
+	}
+	return func() {
+		conn.Close()
+		db.Close()
+	}, nil
+}
+
+// runContainer inicia Postgres en un contenedor. Testcontainers entra en pánico si no
+// encuentra Docker, por lo que el pánico se convierte en error.
+func runContainer(ctx context.Context) (_ string, _ func(), err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	container, err := postgres.Run(ctx, "postgres:16-alpine",
+		postgres.WithDatabase("app_test"),
+		postgres.WithUsername("app"),
+		postgres.WithPassword("app"),
+		postgres.BasicWaitStrategies(),
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	terminate := func() { container.Terminate(context.Background()) }
+
+	connection, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		terminate()
+		return "", nil, err
+	}
+	return connection, terminate, nil
+}
+
+// migrateUp aplica las migraciones a la base de los tests
+func migrateUp(ctx context.Context, dsn string) error {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(ctx)
+	return err
+}
@@ -0,0 +1,99 @@
+// Package tracing configura las trazas de OpenTelemetry: el proveedor con su exportador, el
+// middleware de Gin que abre un span por petición, los callbacks de GORM que abren un span
+// por consulta y Start para los spans de los servicios.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifica a este backend como origen de los spans
+const instrumentationName = "backend"
+
+// Config elige el exportador de las trazas
+type Config struct {
+	ServiceName    string
+	ServiceVersion string
+	// Exporter es none, stdout u otlp
+	Exporter     string
+	OTLPEndpoint string
+	SampleRatio  float64
+}
+
+// Setup registra el proveedor de trazas global y la propagación W3C traceparent y baggage.
+// Retorna una función que envía los spans pendientes y libera el exportador.
+// Con el exportador none los spans se propagan pero no se registran.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case "none", "":
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "otlp":
+		var opts []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("exportador de trazas desconocido: %s", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("exportador de trazas %s: %w", cfg.Exporter, err)
+	}
+
+	provider := NewProvider(cfg, sdktrace.NewBatchSpanProcessor(exporter))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider crea un proveedor de trazas con el processor indicado. Los tests lo usan con
+// un tracetest.InMemoryExporter para inspeccionar los spans.
+func NewProvider(cfg Config, processor sdktrace.SpanProcessor) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		// Si la petición trae un traceparent se respeta la decisión de muestreo del llamador
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+		)),
+	)
+}
+
+// Start abre un span hijo del span que viaja en ctx, como "personaService.Create".
+// Se cierra con End, normalmente con defer, después de registrar el error con RecordError.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// RecordError marca el span como fallido si err no es nil y retorna err sin cambios, para
+// poder escribir "return tracing.RecordError(span, err)"
+func RecordError(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+package tracing
+
+import (
+	"backend/internal/model"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupTest registra un proveedor que guarda los spans en memoria
+func setupTest(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := NewProvider(Config{ServiceName: "test", SampleRatio: 1}, sdktrace.NewSimpleSpanProcessor(exporter))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return exporter
+}
+
+// findSpan busca un span por nombre entre los exportados
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("Se esperaba el span '%s', pero no se encontró entre %d spans", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func attributeValue(span tracetest.SpanStub, key attribute.Key) string {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
+
+// TestMiddlewarePropagatesTraceparent prueba que el span de la petición continúe la traza
+// recibida y que los spans de los servicios queden como hijos
+func TestMiddlewarePropagatesTraceparent(t *testing.T) {
+	// Arrange
+	exporter := setupTest(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.POST("/personas/:id", func(c *gin.Context) {
+		_, span := Start(c.Request.Context(), "personaService.Update")
+		span.End()
+		c.Status(http.StatusInternalServerError)
+	})
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req, _ := http.NewRequest("POST", "/personas/7", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	spans := exporter.GetSpans()
+	server := findSpan(t, spans, "POST /personas/:id")
+	service := findSpan(t, spans, "personaService.Update")
+
+	if server.SpanContext.TraceID().String() != traceID {
+		t.Errorf("Se esperaba la traza %s, pero se obtuvo: %s", traceID, server.SpanContext.TraceID())
+	}
+
+	if server.SpanKind != trace.SpanKindServer || server.Status.Code != codes.Error {
+		t.Errorf("Se esperaba un span de servidor fallido por el status 500, pero se obtuvo: %v %v", server.SpanKind, server.Status)
+	}
+
+	if attributeValue(server, "http.route") != "/personas/:id" || attributeValue(server, "http.response.status_code") != "500" {
+		t.Errorf("Se esperaban la ruta y el status en el span, pero se obtuvo: %v", server.Attributes)
+	}
+
+	if service.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("Se esperaba que el span del servicio fuera hijo del span de la petición")
+	}
+
+	if header := w.Header().Get("traceparent"); !strings.Contains(header, traceID) {
+		t.Errorf("Se esperaba el traceparent en la respuesta, pero se obtuvo: %s", header)
+	}
+}
+
+// TestRegisterGORM prueba que cada consulta abra un span hijo con el SQL ejecutado
+func TestRegisterGORM(t *testing.T) {
+	// Arrange
+	exporter := setupTest(t)
+	// DryRun arma el SQL sin conectarse a la base
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterGORM(db); err != nil {
+		t.Fatalf("Se esperaba nil error, pero se obtuvo: %v", err)
+	}
+
+	ctx, parent := Start(context.Background(), "personaService.Create")
+
+	// Act
+	var persona model.Persona
+	db.WithContext(ctx).Where("email = ?", "ana@test.com").First(&persona)
+	db.WithContext(ctx).Create(&model.Persona{Nombre: "Ana", Email: "ana@test.com", AreaID: 1})
+	parent.End()
+
+	// Assert
+	spans := exporter.GetSpans()
+	query := findSpan(t, spans, "gorm.query personas")
+	create := findSpan(t, spans, "gorm.create personas")
+
+	for _, span := range []tracetest.SpanStub{query, create} {
+		if span.Parent.SpanID() != parent.SpanContext().SpanID() || span.SpanKind != trace.SpanKindClient {
+			t.Errorf("Se esperaba que %s fuera un span de cliente hijo del servicio", span.Name)
+		}
+	}
+
+	if statement := attributeValue(query, "db.query.text"); !strings.Contains(statement, `WHERE email = $1`) || strings.Contains(statement, "ana@test.com") {
+		t.Errorf("Se esperaba el SQL con parámetros y sin valores, pero se obtuvo: %s", statement)
+	}
+
+	if statement := attributeValue(create, "db.query.text"); !strings.Contains(statement, `INSERT INTO "personas"`) {
+		t.Errorf("Se esperaba el INSERT en el span, pero se obtuvo: %s", statement)
+	}
+}
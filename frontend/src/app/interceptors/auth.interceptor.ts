@@ -0,0 +1,36 @@
+import { inject } from '@angular/core';
+import { HttpErrorResponse, HttpInterceptorFn, HttpRequest } from '@angular/common/http';
+import { Router } from '@angular/router';
+import { throwError } from 'rxjs';
+import { catchError, switchMap } from 'rxjs/operators';
+import { AuthService } from '../services/auth.service';
+
+// Agrega el token de acceso a las llamadas a la API y, ante un 401, renueva la sesión una vez
+export const authInterceptor: HttpInterceptorFn = (req, next) => {
+  if (!req.url.startsWith('/api/v1') || req.url.startsWith('/api/v1/auth/')) {
+    return next(req);
+  }
+
+  const authService = inject(AuthService);
+  const router = inject(Router);
+
+  const withToken = (request: HttpRequest<unknown>, token: string | null) =>
+    token ? request.clone({ setHeaders: { Authorization: `Bearer ${token}` } }) : request;
+
+  return next(withToken(req, authService.getAccessToken())).pipe(
+    catchError((error: HttpErrorResponse) => {
+      if (error.status !== 401 || !authService.getRefreshToken()) {
+        return throwError(() => error);
+      }
+
+      return authService.refresh().pipe(
+        switchMap(token => next(withToken(req, token))),
+        catchError(refreshError => {
+          authService.logout();
+          router.navigate(['/login']);
+          return throwError(() => refreshError);
+        })
+      );
+    })
+  );
+};
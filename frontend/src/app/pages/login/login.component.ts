@@ -40,6 +40,23 @@ import { AnimatedBackgroundComponent } from '../../shared/components/animated-ba
               >
             </div>
 
+            <div>
+              <label for="password" class="block text-sm font-medium text-gray-300 mb-2">
+                Contraseña
+              </label>
+              <input 
+                type="password" 
+                id="password" 
+                name="password" 
+                [(ngModel)]="password" 
+                required 
+                class="w-full px-4 py-3 bg-gray-700 border border-gray-600 rounded-lg text-white placeholder-gray-400 focus:outline-none focus:ring-2 focus:ring-indigo-500 focus:border-transparent transition duration-200"
+                placeholder="Ingresa tu contraseña"
+              >
+            </div>
+
+            <p *ngIf="errorMessage" class="text-sm text-red-400 text-center">{{ errorMessage }}</p>
+
             <button cursor-pointer
               type="submit" 
               [disabled]="!loginForm.form.valid || isLoading"
@@ -77,6 +94,7 @@ import { AnimatedBackgroundComponent } from '../../shared/components/animated-ba
 })
 export class LoginComponent {
   userName: string = '';
+  password: string = '';
   isLoading: boolean = false;
   errorMessage: string = '';
 
@@ -85,22 +103,24 @@ export class LoginComponent {
     private router: Router
   ) {}
 
-  async onSubmit() {
-    if (!this.userName.trim()) return;
+  onSubmit() {
+    if (!this.userName.trim() || !this.password) return;
     
     this.isLoading = true;
     this.errorMessage = '';
     
-    try {
-      // Simulamos un pequeño retraso para la animación de carga
-      await new Promise(resolve => setTimeout(resolve, 1000));
-      this.authService.login(this.userName);
-      this.router.navigate(['/']);
-    } catch (error) {
-      this.errorMessage = 'Error al iniciar sesión. Intenta nuevamente.';
-      console.error('Login error:', error);
-    } finally {
-      this.isLoading = false;
-    }
+    this.authService.login(this.userName.trim(), this.password).subscribe({
+      next: () => {
+        this.isLoading = false;
+        this.router.navigate(['/']);
+      },
+      error: (error) => {
+        this.isLoading = false;
+        this.errorMessage = error.status === 401
+          ? 'Usuario o contraseña incorrectos.'
+          : 'Error al iniciar sesión. Intenta nuevamente.';
+        console.error('Login error:', error);
+      }
+    });
   }
 }
\ No newline at end of file
@@ -39,7 +39,7 @@ export class RegistroComponent implements OnInit {
 
   loadAreas(): void {
     console.log('Cargando áreas desde el backend...');
-    this.http.get<{data: Area[]}>('/api/v1/areas').subscribe({
+    this.http.get<{data: Area[]}>('/api/v1/areas?sort=nombre&page_size=100').subscribe({
       next: (response) => {
         console.log('Respuesta completa del backend:', response);
         const areasArray = response.data;
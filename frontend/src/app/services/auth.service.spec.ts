@@ -1,13 +1,19 @@
 import { TestBed } from '@angular/core/testing';
+import { HttpTestingController, provideHttpClientTesting } from '@angular/common/http/testing';
+import { provideHttpClient } from '@angular/common/http';
 import { AuthService } from './auth.service';
 
 describe('AuthService', () => {
   let service: AuthService;
+  let httpMock: HttpTestingController;
   let store: { [key: string]: string } = {};
 
   beforeEach(() => {
-    TestBed.configureTestingModule({});
+    TestBed.configureTestingModule({
+      providers: [provideHttpClient(), provideHttpClientTesting()]
+    });
     service = TestBed.inject(AuthService);
+    httpMock = TestBed.inject(HttpTestingController);
     
     // Mock localStorage
     store = {};
@@ -32,25 +38,45 @@ describe('AuthService', () => {
     spyOn(localStorage, 'removeItem').and.callFake(mockLocalStorage.removeItem);
   });
 
+  afterEach(() => {
+    httpMock.verify();
+  });
+
   it('debe ser creado', () => {
     expect(service).toBeTruthy();
   });
 
-  it('debe guardar el nombre de usuario al hacer login', () => {
+  it('debe guardar el nombre de usuario y los tokens al hacer login', () => {
     // Arrange
     const userName = 'testUser';
     
     // Act
-    service.login(userName);
+    service.login(userName, 'secreto123').subscribe();
+    const req = httpMock.expectOne('/api/v1/auth/login');
+    req.flush({ data: { access_token: 'acceso', refresh_token: 'renovacion', token_type: 'Bearer', expires_in: 900 } });
     
     // Assert
+    expect(req.request.body).toEqual({ username: userName, password: 'secreto123' });
     expect(localStorage.setItem).toHaveBeenCalledWith('userName', userName);
     expect(store['userName']).toBe(userName);
+    expect(store['accessToken']).toBe('acceso');
+    expect(store['refreshToken']).toBe('renovacion');
+  });
+
+  it('no debe guardar la sesión si las credenciales son incorrectas', () => {
+    // Act
+    service.login('testUser', 'incorrecta').subscribe({ error: () => {} });
+    httpMock.expectOne('/api/v1/auth/login').flush({ code: 'invalid_credentials' }, { status: 401, statusText: 'Unauthorized' });
+    
+    // Assert
+    expect(service.isAuthenticated()).toBe(false);
+    expect(store['userName']).toBeUndefined();
   });
 
   it('debe retornar true si el usuario está autenticado', () => {
     // Arrange
     store['userName'] = 'testUser';
+    store['accessToken'] = 'acceso';
     
     // Act
     const isAuth = service.isAuthenticated();
@@ -91,16 +117,23 @@ describe('AuthService', () => {
     expect(retrievedUserName).toBeNull();
   });
 
-  it('debe eliminar el nombre de usuario al hacer logout', () => {
+  it('debe eliminar la sesión al hacer logout y revocarla en el servidor', () => {
     // Arrange
     store['userName'] = 'testUser';
+    store['accessToken'] = 'acceso';
+    store['refreshToken'] = 'renovacion';
     
     // Act
     service.logout();
+    const req = httpMock.expectOne('/api/v1/auth/logout');
+    req.flush(null, { status: 204, statusText: 'No Content' });
     
     // Assert
+    expect(req.request.body).toEqual({ refresh_token: 'renovacion' });
     expect(localStorage.removeItem).toHaveBeenCalledWith('userName');
     expect(store['userName']).toBeUndefined();
+    expect(store['accessToken']).toBeUndefined();
+    expect(store['refreshToken']).toBeUndefined();
   });
 
   it('debe retornar false después de hacer logout', () => {
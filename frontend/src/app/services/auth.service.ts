@@ -1,24 +1,74 @@
-import { Injectable } from '@angular/core';
+import { Injectable, inject } from '@angular/core';
+import { HttpClient } from '@angular/common/http';
+import { Observable } from 'rxjs';
+import { map, tap } from 'rxjs/operators';
+
+export interface TokenPair {
+  access_token: string;
+  refresh_token: string;
+  token_type: string;
+  expires_in: number;
+}
 
 @Injectable({
   providedIn: 'root'
 })
 export class AuthService {
   private readonly USER_KEY = 'userName';
+  private readonly ACCESS_TOKEN_KEY = 'accessToken';
+  private readonly REFRESH_TOKEN_KEY = 'refreshToken';
+  private readonly apiUrl = '/api/v1/auth';
+
+  private http = inject(HttpClient);
+
+  login(userName: string, password: string): Observable<void> {
+    return this.http.post<{ data: TokenPair }>(`${this.apiUrl}/login`, { username: userName, password }).pipe(
+      tap(response => {
+        localStorage.setItem(this.USER_KEY, userName);
+        this.storeTokens(response.data);
+      }),
+      map(() => undefined)
+    );
+  }
 
-  login(userName: string): void {
-    localStorage.setItem(this.USER_KEY, userName);
+  // Canjea el token de renovación por un par nuevo; el anterior deja de ser válido
+  refresh(): Observable<string> {
+    return this.http.post<{ data: TokenPair }>(`${this.apiUrl}/refresh`, { refresh_token: this.getRefreshToken() }).pipe(
+      tap(response => this.storeTokens(response.data)),
+      map(response => response.data.access_token)
+    );
   }
 
   logout(): void {
+    const refreshToken = this.getRefreshToken();
+    if (refreshToken) {
+      // Revocar la sesión en el servidor; la sesión local se cierra aunque falle
+      this.http.post(`${this.apiUrl}/logout`, { refresh_token: refreshToken }).subscribe({ error: () => {} });
+    }
+
     localStorage.removeItem(this.USER_KEY);
+    localStorage.removeItem(this.ACCESS_TOKEN_KEY);
+    localStorage.removeItem(this.REFRESH_TOKEN_KEY);
   }
 
   isAuthenticated(): boolean {
-    return !!this.getUserName();
+    return !!this.getUserName() && !!this.getAccessToken();
   }
 
   getUserName(): string | null {
     return localStorage.getItem(this.USER_KEY);
   }
-}
\ No newline at end of file
+
+  getAccessToken(): string | null {
+    return localStorage.getItem(this.ACCESS_TOKEN_KEY);
+  }
+
+  getRefreshToken(): string | null {
+    return localStorage.getItem(this.REFRESH_TOKEN_KEY);
+  }
+
+  private storeTokens(tokens: TokenPair): void {
+    localStorage.setItem(this.ACCESS_TOKEN_KEY, tokens.access_token);
+    localStorage.setItem(this.REFRESH_TOKEN_KEY, tokens.refresh_token);
+  }
+}